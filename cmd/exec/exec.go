@@ -1,30 +1,29 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"sync"
-	"syscall"
 
 	"github.com/troplet/internal/shared"
 	"github.com/troplet/pkg/exec"
+	"github.com/troplet/pkg/exec/cgroups"
+	"github.com/troplet/pkg/exec/mountfs"
 )
 
 func main() {
-	path, err := GetFilesystemMount("./")
+	devices, err := mountfs.GetBackingDevices("./")
 	if err != nil {
 		return
 	}
-	fmt.Printf("Got path: %s\n", path)
-	major, minor, err := GetDeviceNumbers(path)
-	if err != nil {
-		return
+	fmt.Printf("Got devices: %v\n", devices)
+	ioSpec := cgroups.IOSpec{}
+	for _, device := range devices {
+		ioSpec.Devices = append(ioSpec.Devices, cgroups.IODeviceLimit{
+			DeviceMajorNum: device.MajorNum, DeviceMinorNum: device.MinorNum,
+			ReadBps: 4 * 1024, WriteBps: 1024})
 	}
-	fmt.Printf("Got major=%d, minor=%d\n", major, minor)
 	var wg sync.WaitGroup
 	stdoutChan := make(chan []byte, 0)
 	stderrChan := make(chan []byte, 0)
@@ -58,13 +57,13 @@ func main() {
 			exec.WithStdoutChan(stdoutChan), exec.WithStderrChan(stderrChan),
 			exec.WithNewRootBase("./"), exec.WithCPULimit(100, 1000),
 			exec.WithUsePIDNS(), exec.WithUseNetNS(), exec.WithMemoryLimit(4*1024),
-			exec.WithIOLimits(major, minor, 4*1024, 1024))
+			exec.WithIOSpec(ioSpec))
 	} else if len(os.Args) > 2 {
 		cmd, err = exec.NewCommand(os.Args[1], os.Args[2:],
 			exec.WithStdoutChan(stdoutChan), exec.WithStderrChan(stderrChan),
 			exec.WithNewRootBase("./"), exec.WithCPULimit(100, 1000),
 			exec.WithUsePIDNS(), exec.WithUseNetNS(), exec.WithMemoryLimit(4*1024),
-			exec.WithIOLimits(major, minor, 4*1024, 1024))
+			exec.WithIOSpec(ioSpec))
 	}
 	if err != nil {
 		fmt.Printf("error in cmd creation: %v", err.Error())
@@ -91,80 +90,3 @@ func main() {
 	cmd.Finish()
 	fmt.Printf(cmd.String() + "\n")
 }
-
-func GetFilesystemMount(path string) (string, error) {
-	// Get absolute path to handle relative paths
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path: %v", err)
-	}
-
-	// Read /proc/mounts
-	file, err := os.Open("/proc/mounts")
-	if err != nil {
-		return "", fmt.Errorf("failed to read /proc/mounts: %v", err)
-	}
-	defer file.Close()
-
-	// Find the most specific mount point
-	var bestMatch string
-	bestLen := -1
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) < 3 {
-			continue
-		}
-
-		mountPoint := fields[1]
-		// Unescape the mount point
-		mountPoint = strings.ReplaceAll(mountPoint, "\\040", " ")
-		mountPoint = strings.ReplaceAll(mountPoint, "\\011", "\t")
-
-		// Check if this mount point is a parent of our path
-		rel, err := filepath.Rel(mountPoint, absPath)
-		if err != nil {
-			continue
-		}
-
-		// Skip if path goes outside mount point
-		if strings.HasPrefix(rel, "..") {
-			continue
-		}
-
-		// Keep track of the longest (most specific) mount point
-		if len(mountPoint) > bestLen {
-			bestLen = len(mountPoint)
-			bestMatch = mountPoint
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading /proc/mounts: %v", err)
-	}
-
-	if bestLen == -1 {
-		return "", fmt.Errorf("no filesystem found for %s", path)
-	}
-
-	return bestMatch, nil
-}
-
-func GetDeviceNumbers(dirPath string) (int32, int32, error) {
-	info, err := os.Stat(dirPath)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to stat directory: %v", err)
-	}
-
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
-		return 0, 0, fmt.Errorf("failed to get system stat info")
-	}
-
-	// Get device numbers from the filesystem device ID
-	major := int32((stat.Dev >> 8) & 0xfff)
-	minor := int32(stat.Dev & 0xff)
-
-	return major, minor, nil
-}
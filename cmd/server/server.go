@@ -5,31 +5,91 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 
 	"github.com/troplet/internal/server"
 	"github.com/troplet/internal/shared"
 )
 
 func main() {
-	var address, certsDir string
+	var address, certsDir, configPath string
 	// Root command starts the server
 	var rootCmd = &cobra.Command{
 		Run: func(cmd *cobra.Command, args []string) {
-			config := server.Config{
-				Address:      address,
-				CABundlePath: filepath.Join(certsDir, shared.ServerDefaultCAFile),
-				CertPath:     filepath.Join(certsDir, shared.ServerDefaultCertFile),
-				CertKeyPath:  filepath.Join(certsDir, shared.ServerDefaultCertKeyFile),
+			loadConfig := func() (*server.Config, error) {
+				config := server.DefaultConfig()
+				if configPath != "" {
+					var err error
+					if config, err = server.LoadConfig(configPath); err != nil {
+						return nil, err
+					}
+				}
+				// Explicit CLI flags take precedence over the file
+				if cmd.Flags().Changed("address") || configPath == "" {
+					config.Address = address
+				}
+				if cmd.Flags().Changed("certs-dir") || configPath == "" {
+					config.CABundlePath = filepath.Join(certsDir, shared.ServerDefaultCAFile)
+					config.CertPath = filepath.Join(certsDir, shared.ServerDefaultCertFile)
+					config.CertKeyPath = filepath.Join(certsDir, shared.ServerDefaultCertKeyFile)
+				}
+				if err := config.Validate(); err != nil {
+					return nil, err
+				}
+
+				return config, nil
+			}
+			config, err := loadConfig()
+			if err != nil {
+				fmt.Printf("invalid configuration: %v\n", err)
+				return
 			}
-			logger := shared.CreateLogger()
+			// Validated already
+			level, _ := config.GetLogLevel()
+			logLevel := zap.NewAtomicLevelAt(level)
+			logger := shared.CreateLoggerWithLevel(logLevel)
 			defer logger.Sync()
-			jobManager, err := server.NewJobManager(logger)
+			store, err := server.NewJobStore(config.JobStorePath)
+			if err != nil {
+				logger.Errorf("Failed creating job store: %v", err)
+				return
+			}
+			metrics := server.NewMetrics()
+			metrics.Publish("troplet")
+			if config.MetricsAddress != "" {
+				if err := server.ServeMetrics(config.MetricsAddress); err != nil {
+					logger.Errorf(err.Error())
+					return
+				}
+			}
+			jobManager, err := server.NewJobManager(logger, config, store, metrics)
 			if err != nil {
+				logger.Errorf("Failed creating job manager: %v", err)
 				return
 			}
 			defer jobManager.Finish()
-			server := server.NewServer(&config, logger, jobManager)
-			defer server.Finish()
+			server := server.NewServer(config, logger, jobManager)
+			shared.RegisterReloadSigCallback(func() {
+				config, err := loadConfig()
+				if err != nil {
+					logger.Errorf("Failed reloading configuration: %v", err)
+					return
+				}
+				if err := server.Reload(config); err != nil {
+					logger.Errorf("Failed applying configuration: %v", err)
+					return
+				}
+				level, _ := config.GetLogLevel()
+				logLevel.SetLevel(level)
+				logger.Infof("Reloaded config: " + config.String())
+			})
+			// Jobs are stopped first as attached clients keep
+			// the server from stopping till the jobs terminate
+			shared.RegisterShutdownSigCallback(func() {
+				logger.Infof("Shutting down, stopping all the jobs")
+				jobManager.Shutdown()
+				server.Finish()
+			})
 			logger.Infof("Starting server with config: " + config.String())
 			if err := server.Start(); err != nil {
 				logger.Errorf(err.Error())
@@ -42,8 +102,11 @@ func main() {
 	// Certificates directory
 	rootCmd.PersistentFlags().StringVarP(&certsDir, "certs-dir", "c",
 		shared.ServerDefaultCertsDir, "Path of directory where certificates are located")
+	// Configuration file, reloaded on SIGHUP
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "f", "",
+		"Path of YAML configuration file")
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Errorf("failed executing server: %w", err)
+		fmt.Printf("failed executing server: %v\n", err)
 	}
 }
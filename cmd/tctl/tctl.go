@@ -2,16 +2,33 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/troplet/internal/client"
 	"github.com/troplet/internal/shared"
+	"github.com/troplet/pkg/proto"
 )
 
 func main() {
 	var serverAddress, certsDir string
+	var limits proto.ResourceLimits
+	var openStdin, tty bool
+	var offset int64
+	var stdinPath string
+	var signal string
+	var gracePeriod time.Duration
+	var timeout time.Duration
+	var networkMode proto.NetworkMode
+	var egress []*proto.EgressRule
+	var watch bool
+	var interval time.Duration
 	// Root command list remote jobs by default
 	var rootCmd = &cobra.Command{
 		Run: func(cmd *cobra.Command, args []string) {
@@ -42,6 +59,39 @@ func main() {
 			})
 		},
 	}
+	var statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Gets remote job resource usage",
+		Long: "Gets remote job resource usage. Totals captured at exit are " +
+			"shown for terminated jobs.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				if watch {
+					c.WatchJobStats(args[0], interval)
+					return
+				}
+				c.GetJobStats(args[0])
+			})
+		},
+	}
+	statsCmd.Flags().BoolVarP(&watch, "watch", "w", false,
+		"Keep showing resource usage till the job terminates")
+	statsCmd.Flags().DurationVar(&interval, "interval", time.Second,
+		"Sampling interval with --watch")
+	var topCmd = &cobra.Command{
+		Use:   "top",
+		Short: "Shows resource usage of remote running jobs",
+		Long:  "Shows resource usage of remote running jobs, refreshed till interrupted",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				c.Top(interval)
+			})
+		},
+	}
+	topCmd.Flags().DurationVar(&interval, "interval", 2*time.Second,
+		"Refresh interval")
 	var launchCmd = &cobra.Command{
 		Use:   "launch",
 		Short: "Starts job on server",
@@ -49,34 +99,131 @@ func main() {
 		Args:  cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			executeCommand(serverAddress, certsDir, func(c *client.Client) {
-				c.LaunchJob(args[0], args[1:])
+				c.LaunchJob(&proto.LaunchJobRequest{Command: args[0], Args: args[1:],
+					Limits: getRequestedLimits(&limits), OpenStdin: openStdin,
+					Timeout: getRequestedTimeout(cmd, timeout), NetworkMode: networkMode,
+					Egress: egress})
+			})
+		},
+	}
+	addLimitsFlags(launchCmd, &limits)
+	addTimeoutFlag(launchCmd, &timeout)
+	addNetworkFlags(launchCmd, &networkMode, &egress)
+	launchCmd.Flags().BoolVarP(&openStdin, "stdin", "i", false,
+		"Keep stdin of the job open to send input with attach --stdin")
+	var execCmd = &cobra.Command{
+		Use:   "exec",
+		Short: "Runs job on server and streams its output till it terminates",
+		Long: "Runs job on server and streams its output till it terminates. " +
+			"With --tty the job runs on a terminal and the local terminal is " +
+			"put in raw mode, for example \"exec -it bash\".",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				c.ExecJob(&proto.LaunchJobRequest{Command: args[0], Args: args[1:],
+					Limits: getRequestedLimits(&limits), OpenStdin: openStdin, Tty: tty,
+					Timeout: getRequestedTimeout(cmd, timeout), NetworkMode: networkMode,
+					Egress: egress})
 			})
 		},
 	}
+	addLimitsFlags(execCmd, &limits)
+	addTimeoutFlag(execCmd, &timeout)
+	addNetworkFlags(execCmd, &networkMode, &egress)
+	execCmd.Flags().BoolVarP(&openStdin, "stdin", "i", false,
+		"Send the local stdin to the job")
+	execCmd.Flags().BoolVarP(&tty, "tty", "t", false,
+		"Run the job on a terminal")
 	var terminateCmd = &cobra.Command{
 		Use:   "terminate",
 		Short: "Terminates remote running job",
-		Long:  "Terminates remote running job",
+		Long: "Terminates remote running job. The job gets SIGTERM and is " +
+			"killed if it does not exit within the grace period.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				var requested *time.Duration
+				if cmd.Flags().Changed("grace-period") {
+					requested = &gracePeriod
+				}
+				c.TerminateJob(args[0], requested)
+			})
+		},
+	}
+	terminateCmd.Flags().DurationVar(&gracePeriod, "grace-period", 0,
+		"Time the job gets to exit after SIGTERM before it is killed, "+
+			"server default if not set")
+	var killCmd = &cobra.Command{
+		Use:   "kill",
+		Short: "Sends signal to remote running job",
+		Long: "Sends signal to remote running job. The job does not get " +
+			"the signals it has no handler for, except KILL. Use pause and " +
+			"resume instead of STOP and CONT.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				c.SignalJob(args[0], signal)
+			})
+		},
+	}
+	killCmd.Flags().StringVar(&signal, "signal", "TERM",
+		"Signal name such as HUP, INT, TERM or USR1")
+	var pauseCmd = &cobra.Command{
+		Use:   "pause",
+		Short: "Pauses remote running job",
+		Long:  "Pauses remote running job by freezing all of its processes",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			executeCommand(serverAddress, certsDir, func(c *client.Client) {
+				c.PauseJob(args[0])
+			})
+		},
+	}
+	var resumeCmd = &cobra.Command{
+		Use:   "resume",
+		Short: "Resumes remote paused job",
+		Long:  "Resumes remote paused job",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			executeCommand(serverAddress, certsDir, func(c *client.Client) {
-				c.TerminateJob(args[0])
+				c.ResumeJob(args[0])
 			})
 		},
 	}
 	var attachCmd = &cobra.Command{
 		Use:   "attach",
-		Short: "Attaches to remote running job and gets its standard error and output",
-		Long:  "Attaches to remote running job and gets its standard error and output",
-		Args:  cobra.ExactArgs(1),
+		Short: "Attaches to remote job and gets its standard error and output",
+		Long: "Attaches to remote job and gets its standard error and output. " +
+			"Output produced so far is replayed before the live output.",
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			executeCommand(serverAddress, certsDir, func(c *client.Client) {
-				c.AttachJob(args[0])
+				if stdinPath == "" {
+					c.AttachJob(args[0], offset)
+					return
+				}
+				stdin := os.Stdin
+				if stdinPath != "-" {
+					file, err := os.Open(stdinPath)
+					if err != nil {
+						fmt.Printf("failed opening stdin: %v\n", err)
+						return
+					}
+					defer file.Close()
+					stdin = file
+				}
+				c.InteractJob(args[0], offset, stdin)
 			})
 		},
 	}
+	attachCmd.Flags().Int64Var(&offset, "offset", 0,
+		"Output offset to replay from")
+	attachCmd.Flags().StringVar(&stdinPath, "stdin", "",
+		"Send the terminal input, or the given file, to stdin of the job")
+	attachCmd.Flags().Lookup("stdin").NoOptDefVal = "-"
 
-	rootCmd.AddCommand(listCmd, getStatusCmd, launchCmd, terminateCmd, attachCmd)
+	rootCmd.AddCommand(listCmd, getStatusCmd, statsCmd, topCmd, launchCmd, execCmd,
+		terminateCmd, killCmd, pauseCmd, resumeCmd, attachCmd)
 	// Persistent CLI flags applicable for all the commands
 	// Server address
 	rootCmd.PersistentFlags().StringVarP(&serverAddress, "server-address", "s",
@@ -86,10 +233,229 @@ func main() {
 		shared.ClientDefaultCertsDir, "Path of directory where certificates are located")
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Errorf("failed executing command: %w", err)
+		fmt.Printf("failed executing command: %v\n", err)
 	}
 }
 
+// Adds flags of the limits requested at launch
+func addLimitsFlags(cmd *cobra.Command, limits *proto.ResourceLimits) {
+	// Flags after the job command belong to the job
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().Int64Var(&limits.CpuQuotaMs, "cpu", 0,
+		"CPU time in milliseconds the job may consume in each period")
+	cmd.Flags().Int64Var(&limits.CpuPeriodMs, "cpu-period", 0,
+		"CPU period in milliseconds")
+	cmd.Flags().Int64Var(&limits.CpuWeight, "cpu-weight", 0,
+		"CPU share relative to the other jobs, in [1, 10000]")
+	cmd.Flags().StringVar(&limits.CpusetCpus, "cpuset-cpus", "",
+		"CPUs to pin the job to, such as 0-3,6")
+	cmd.Flags().StringVar(&limits.CpusetMems, "cpuset-mems", "",
+		"Memory nodes to pin the job to, such as 0")
+	cmd.Flags().Int64Var(&limits.MemoryKb, "memory", 0,
+		"Maximum memory in KB")
+	cmd.Flags().Int64Var(&limits.MemoryHighKb, "memory-high", 0,
+		"Memory in KB beyond which the job is throttled instead of OOM-killed")
+	cmd.Flags().Int64Var(&limits.MemoryLowKb, "memory-low", 0,
+		"Memory in KB protected from reclaim on best effort basis")
+	cmd.Flags().Int64Var(&limits.MemoryMinKb, "memory-min", 0,
+		"Memory in KB protected from reclaim")
+	cmd.Flags().Var(&optionalInt64Flag{value: &limits.MemorySwapMaxKb}, "memory-swap",
+		"Maximum swap in KB, zero disables swap")
+	cmd.Flags().BoolVar(&limits.MemoryOomGroup, "memory-oom-group", false,
+		"Kill all the processes of the job together on OOM")
+	cmd.Flags().Int64Var(&limits.ReadBps, "read-bps", 0,
+		"Maximum read bytes per second")
+	cmd.Flags().Int64Var(&limits.WriteBps, "write-bps", 0,
+		"Maximum write bytes per second")
+	cmd.Flags().Int64Var(&limits.ReadIops, "read-iops", 0,
+		"Maximum read operations per second")
+	cmd.Flags().Int64Var(&limits.WriteIops, "write-iops", 0,
+		"Maximum write operations per second")
+	cmd.Flags().Int64Var(&limits.IoWeight, "io-weight", 0,
+		"IO share relative to the other jobs, in [1, 10000]")
+	cmd.Flags().Var((*ioDevicesFlag)(&limits.IoDevices), "io-device",
+		"Limits on a block device as MAJOR:MINOR[,rbps=N][,wbps=N][,riops=N]"+
+			"[,wiops=N][,weight=N], can be repeated")
+	cmd.Flags().Int64Var(&limits.MaxPids, "pids", 0,
+		"Maximum number of processes and threads")
+}
+
+// Flag of a value that is set only if the flag is given,
+// since zero is a valid value too
+type optionalInt64Flag struct {
+	value **int64
+}
+
+func (f *optionalInt64Flag) String() string {
+	if f.value == nil || *f.value == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(**f.value, 10)
+}
+
+func (f *optionalInt64Flag) Set(value string) error {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	*f.value = &n
+
+	return nil
+}
+
+func (f *optionalInt64Flag) Type() string {
+	return "int64"
+}
+
+// Repeatable flag of per device IO limits
+type ioDevicesFlag []*proto.IODeviceLimits
+
+func (f *ioDevicesFlag) String() string {
+	devices := []string{}
+	for _, d := range *f {
+		devices = append(devices, fmt.Sprintf("%d:%d", d.DeviceMajorNum, d.DeviceMinorNum))
+	}
+
+	return strings.Join(devices, ",")
+}
+
+func (f *ioDevicesFlag) Set(value string) error {
+	fields := strings.Split(value, ",")
+	device := &proto.IODeviceLimits{}
+	if _, err := fmt.Sscanf(fields[0], "%d:%d",
+		&device.DeviceMajorNum, &device.DeviceMinorNum); err != nil {
+		return fmt.Errorf("invalid device %q: %w", fields[0], err)
+	}
+	for _, field := range fields[1:] {
+		key, v, _ := strings.Cut(field, "=")
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value of %q: %w", key, err)
+		}
+		switch key {
+		case "rbps":
+			device.ReadBps = n
+		case "wbps":
+			device.WriteBps = n
+		case "riops":
+			device.ReadIops = n
+		case "wiops":
+			device.WriteIops = n
+		case "weight":
+			device.Weight = n
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+	*f = append(*f, device)
+
+	return nil
+}
+
+func (f *ioDevicesFlag) Type() string {
+	return "device-limits"
+}
+
+// Adds flags of the network requested at launch
+func addNetworkFlags(cmd *cobra.Command, mode *proto.NetworkMode,
+	egress *[]*proto.EgressRule) {
+	cmd.Flags().Var((*networkModeFlag)(mode), "network",
+		"Network mode of the job, one of none, loopback, bridged or nat, "+
+			"server default if not set")
+	cmd.Flags().Var((*egressFlag)(egress), "egress",
+		"Destination the job may send to as CIDR[:PORT,PORT...], can be repeated, "+
+			"server policy if not set")
+}
+
+type networkModeFlag proto.NetworkMode
+
+func (f *networkModeFlag) String() string {
+	if proto.NetworkMode(*f) == proto.NetworkMode_NETWORK_MODE_UNSPECIFIED {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimPrefix(proto.NetworkMode(*f).String(), "NETWORK_MODE_"))
+}
+
+func (f *networkModeFlag) Set(value string) error {
+	mode, found := proto.NetworkMode_value["NETWORK_MODE_"+strings.ToUpper(value)]
+	if !found || mode == int32(proto.NetworkMode_NETWORK_MODE_UNSPECIFIED) {
+		return fmt.Errorf("invalid network mode %q", value)
+	}
+	*f = networkModeFlag(mode)
+
+	return nil
+}
+
+func (f *networkModeFlag) Type() string {
+	return "mode"
+}
+
+// Repeatable flag of egress rules
+type egressFlag []*proto.EgressRule
+
+func (f *egressFlag) String() string {
+	rules := []string{}
+	for _, rule := range *f {
+		rules = append(rules, rule.Cidr)
+	}
+
+	return strings.Join(rules, ",")
+}
+
+func (f *egressFlag) Set(value string) error {
+	cidr, ports, found := strings.Cut(value, ":")
+	rule := &proto.EgressRule{Cidr: cidr}
+	if found {
+		for _, port := range strings.Split(ports, ",") {
+			n, err := strconv.ParseUint(port, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid port %q: %w", port, err)
+			}
+			rule.Ports = append(rule.Ports, uint32(n))
+		}
+	}
+	*f = append(*f, rule)
+
+	return nil
+}
+
+func (f *egressFlag) Type() string {
+	return "egress"
+}
+
+func addTimeoutFlag(cmd *cobra.Command, timeout *time.Duration) {
+	cmd.Flags().DurationVar(timeout, "timeout", 0,
+		"Wall-clock time after which the job is terminated, "+
+			"server default if not set")
+}
+
+// Returns timeout only if requested, server will apply its default otherwise
+func getRequestedTimeout(cmd *cobra.Command, timeout time.Duration) *durationpb.Duration {
+	if !cmd.Flags().Changed("timeout") {
+		return nil
+	}
+
+	return durationpb.New(timeout)
+}
+
+// Returns limits only if any of these were requested,
+// server will apply its defaults otherwise
+func getRequestedLimits(limits *proto.ResourceLimits) *proto.ResourceLimits {
+	if limits.CpuQuotaMs != 0 || limits.CpuPeriodMs != 0 ||
+		limits.MemoryKb != 0 || limits.ReadBps != 0 || limits.WriteBps != 0 ||
+		limits.MaxPids != 0 || limits.MemoryHighKb != 0 || limits.MemoryLowKb != 0 ||
+		limits.MemoryMinKb != 0 || limits.MemorySwapMaxKb != nil || limits.MemoryOomGroup ||
+		limits.CpuWeight != 0 || limits.CpusetCpus != "" || limits.CpusetMems != "" ||
+		limits.ReadIops != 0 || limits.WriteIops != 0 || limits.IoWeight != 0 ||
+		len(limits.IoDevices) != 0 {
+		return limits
+	}
+
+	return nil
+}
+
 func executeCommand(serverAddress, certsDir string, cmdCB func(client *client.Client)) {
 	config := client.Config{
 		ServerAddress: serverAddress,
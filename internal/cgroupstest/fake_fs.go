@@ -0,0 +1,502 @@
+// Package cgroupstest provides a fake cgroup2 filesystem for tests
+package cgroupstest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// FakeFS emulates cgroup2 filesystem in a plain directory, so that
+// control groups can be managed without privileges such as in tests.
+// Control files are regular files and every write is recorded. Writes
+// with side effects, such as attaching, freezing and killing processes,
+// are emulated while limits are only stored, not enforced. Pressure
+// triggers never fire.
+type FakeFS struct {
+	root   string
+	lock   sync.Mutex
+	writes []FakeWrite
+}
+
+// Write recorded by FakeFS, with path relative to its root
+type FakeWrite struct {
+	Path  string
+	Value string
+}
+
+// Returns fake cgroup2 filesystem mounted at given existing directory,
+// with given controllers available in the root group
+func NewFakeFS(root string, controllers []string) (*FakeFS, error) {
+	fs := &FakeFS{root: filepath.Clean(root)}
+	cpus := "0"
+	if runtime.NumCPU() > 1 {
+		cpus = fmt.Sprintf("0-%d", runtime.NumCPU()-1)
+	}
+	files := map[string]string{
+		"cgroup.controllers":     strings.Join(controllers, " "),
+		"cgroup.subtree_control": "",
+		"cgroup.procs":           "",
+		"cpuset.cpus.effective":  cpus,
+		"cpuset.mems.effective":  "0",
+	}
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(fs.root, name),
+			[]byte(value+"\n"), 0644); err != nil {
+			return nil, fmt.Errorf("failed writing to %s: %w", name, err)
+		}
+	}
+
+	return fs, nil
+}
+
+// Returns all the writes so far
+func (fs *FakeFS) Writes() []FakeWrite {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	return slices.Clone(fs.writes)
+}
+
+func (fs *FakeFS) GetMountPath() (string, error) {
+	return fs.root, nil
+}
+
+func (fs *FakeFS) ReadFile(filePath string) ([]byte, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	var content []byte
+	var err error
+	switch filepath.Base(filePath) {
+	case "cgroup.procs":
+		var pids []int
+		if pids, err = fs.readPIDs(filepath.Dir(filePath), false); err == nil {
+			content = []byte(formatPIDs(pids))
+		}
+	case "pids.current":
+		var pids []int
+		if pids, err = fs.readPIDs(filepath.Dir(filePath), true); err == nil {
+			content = []byte(strconv.Itoa(len(pids)) + "\n")
+		}
+	case "cgroup.events":
+		content, err = fs.readEvents(filepath.Dir(filePath))
+	case "cgroup.controllers":
+		var controllers []string
+		if controllers, err = fs.readControllers(filepath.Dir(filePath)); err == nil {
+			content = []byte(strings.Join(controllers, " ") + "\n")
+		}
+	default:
+		content, err = os.ReadFile(filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", filePath, err)
+	}
+
+	return content, nil
+}
+
+func (fs *FakeFS) WriteFile(filePath, value string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if rel, err := filepath.Rel(fs.root, filePath); err == nil {
+		fs.writes = append(fs.writes, FakeWrite{rel, value})
+	}
+	group := filepath.Dir(filePath)
+	var err error
+	switch filepath.Base(filePath) {
+	case "cgroup.subtree_control":
+		err = fs.writeSubtreeControl(group, value)
+	case "cgroup.procs":
+		err = fs.attach(group, value)
+	case "cgroup.freeze":
+		err = fs.freeze(group, value)
+	case "cgroup.kill":
+		err = fs.signal(group, syscall.SIGKILL)
+	default:
+		if _, err = os.Stat(group); err == nil {
+			err = os.WriteFile(filePath, []byte(value+"\n"), 0644)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed writing to %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Creates the group with the control files of the controllers
+// enabled by its parent
+func (fs *FakeFS) Mkdir(dirPath string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if err := fs.mkdir(dirPath); err != nil {
+		return fmt.Errorf("failed to create cgroup path %s: %w", dirPath, err)
+	}
+
+	return nil
+}
+
+func (fs *FakeFS) mkdir(dirPath string) error {
+	controllers, err := fs.readControllers(dirPath)
+	if err != nil {
+		return err
+	}
+	if err := os.Mkdir(dirPath, 0755); err != nil {
+		return err
+	}
+	pressure := "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n" +
+		"full avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+	files := map[string]string{
+		"cgroup.subtree_control": "",
+		"cgroup.procs":           "",
+		"cgroup.freeze":          "0",
+		"cpu.stat":               "usage_usec 0\nuser_usec 0\nsystem_usec 0",
+		"cpu.pressure":           pressure,
+		"memory.pressure":        pressure,
+		"io.pressure":            pressure,
+	}
+	if err := writeFiles(dirPath, files); err != nil {
+		return err
+	}
+	for _, controller := range controllers {
+		if err := createControllerFiles(dirPath, controller); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Creates the files of the controller in the group once enabled
+// by its parent
+func createControllerFiles(group, controller string) error {
+	files := map[string]string{}
+	switch controller {
+	case "cpu":
+		files["cpu.stat"] = "usage_usec 0\nuser_usec 0\nsystem_usec 0\n" +
+			"nr_periods 0\nnr_throttled 0\nthrottled_usec 0"
+	case "cpuset":
+		for _, name := range []string{"cpuset.cpus.effective", "cpuset.mems.effective"} {
+			effective, err := os.ReadFile(filepath.Join(filepath.Dir(group), name))
+			if err != nil {
+				return err
+			}
+			files[name] = strings.TrimSpace(string(effective))
+		}
+	case "memory":
+		files["memory.current"] = "0"
+		files["memory.peak"] = "0"
+		files["memory.stat"] = "anon 0\nfile 0\nkernel 0"
+		files["memory.events"] = "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0"
+	case "io":
+		files["io.stat"] = ""
+	case "pids":
+		files["pids.events"] = "max 0"
+	}
+
+	return writeFiles(group, files)
+}
+
+func writeFiles(dirPath string, files map[string]string) error {
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(dirPath, name),
+			[]byte(value+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns controllers available in the group, the ones enabled by
+// its parent unless the group is the root
+func (fs *FakeFS) readControllers(group string) ([]string, error) {
+	filePath := filepath.Join(filepath.Dir(group), "cgroup.subtree_control")
+	if group == fs.root {
+		filePath = filepath.Join(group, "cgroup.controllers")
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(content)), nil
+}
+
+// Removes the group, which must have no processes or children left
+func (fs *FakeFS) Remove(dirPath string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if err := fs.remove(dirPath); err != nil {
+		return fmt.Errorf("failed to remove cgroup path %s: %w", dirPath, err)
+	}
+
+	return nil
+}
+
+func (fs *FakeFS) remove(dirPath string) error {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return syscall.EBUSY
+		}
+	}
+	pids, err := fs.readPIDs(dirPath, false)
+	if err != nil {
+		return err
+	}
+	if len(pids) != 0 {
+		return syscall.EBUSY
+	}
+
+	return os.RemoveAll(dirPath)
+}
+
+// Opens the file as is, except for registering a pressure trigger
+// which opens a side file that never gets a priority event
+func (fs *FakeFS) OpenFile(filePath string, flag int) (*os.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) != 0 && strings.HasSuffix(filePath, ".pressure") {
+		if _, err := os.Stat(filePath); err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+		}
+		filePath += ".trigger"
+		flag = os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	}
+	file, err := os.OpenFile(filePath, flag, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+
+	return file, nil
+}
+
+// Processes are attached with AddProcess once started
+func (fs *FakeFS) CanCloneInto() bool {
+	return false
+}
+
+// Enables or disables space separated controllers prefixed with
+// + or -, which must be available in the group. Files of enabled
+// controllers are created in the existing children, and left as is
+// once disabled.
+func (fs *FakeFS) writeSubtreeControl(group, value string) error {
+	available, err := fs.readControllers(group)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(group)
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(group, "cgroup.subtree_control")
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	enabled := strings.Fields(string(content))
+	for _, field := range strings.Fields(value) {
+		controller := field[1:]
+		if !slices.Contains(available, controller) {
+			return syscall.ENOENT
+		}
+		switch field[0] {
+		case '+':
+			if slices.Contains(enabled, controller) {
+				continue
+			}
+			enabled = append(enabled, controller)
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				if err := createControllerFiles(filepath.Join(group, entry.Name()),
+					controller); err != nil {
+					return err
+				}
+			}
+		case '-':
+			enabled = slices.DeleteFunc(enabled,
+				func(c string) bool { return c == controller })
+		default:
+			return syscall.EINVAL
+		}
+	}
+
+	return os.WriteFile(filePath, []byte(strings.Join(enabled, " ")+"\n"), 0644)
+}
+
+// Moves the process to the group from any other group
+func (fs *FakeFS) attach(group, value string) error {
+	pid, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return syscall.EINVAL
+	}
+	if _, err := os.Stat(group); err != nil {
+		return err
+	}
+	if err := filepath.WalkDir(fs.root, func(dirPath string, entry os.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+		pids, err := fs.readPIDs(dirPath, false)
+		if err != nil {
+			return err
+		}
+		if dirPath == group {
+			if !slices.Contains(pids, pid) {
+				pids = append(pids, pid)
+			}
+		} else if i := slices.Index(pids, pid); i != -1 {
+			pids = slices.Delete(pids, i, i+1)
+		} else {
+			return nil
+		}
+
+		return os.WriteFile(filepath.Join(dirPath, "cgroup.procs"),
+			[]byte(formatPIDs(pids)), 0644)
+	}); err != nil {
+		return err
+	}
+	// Joining a frozen group freezes the process
+	frozen, err := os.ReadFile(filepath.Join(group, "cgroup.freeze"))
+	if err == nil && strings.TrimSpace(string(frozen)) == "1" {
+		return signalProcess(pid, syscall.SIGSTOP)
+	}
+
+	return nil
+}
+
+func (fs *FakeFS) freeze(group, value string) error {
+	sig := syscall.SIGCONT
+	switch strings.TrimSpace(value) {
+	case "0":
+	case "1":
+		sig = syscall.SIGSTOP
+	default:
+		return syscall.EINVAL
+	}
+	if err := os.WriteFile(filepath.Join(group, "cgroup.freeze"),
+		[]byte(value+"\n"), 0644); err != nil {
+		return err
+	}
+
+	return fs.signal(group, sig)
+}
+
+// Sends the signal to the processes of the group and its descendants
+func (fs *FakeFS) signal(group string, sig syscall.Signal) error {
+	pids, err := fs.readPIDs(group, true)
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		if err := signalProcess(pid, sig); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads cgroup.events with populated and frozen state of the group
+func (fs *FakeFS) readEvents(group string) ([]byte, error) {
+	pids, err := fs.readPIDs(group, true)
+	if err != nil {
+		return nil, err
+	}
+	frozen, err := os.ReadFile(filepath.Join(group, "cgroup.freeze"))
+	if err != nil {
+		return nil, err
+	}
+	populated := "0"
+	if len(pids) != 0 {
+		populated = "1"
+	}
+
+	return []byte("populated " + populated + "\nfrozen " +
+		strings.TrimSpace(string(frozen)) + "\n"), nil
+}
+
+// Reads live processes attached to the group, and to its descendants
+// if recursive. Processes that exited are dropped.
+func (fs *FakeFS) readPIDs(group string, recursive bool) ([]int, error) {
+	content, err := os.ReadFile(filepath.Join(group, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+	ret := []int{}
+	for _, field := range strings.Fields(string(content)) {
+		if pid, err := strconv.Atoi(field); err == nil && isAlive(pid) {
+			ret = append(ret, pid)
+		}
+	}
+	if !recursive {
+		return ret, nil
+	}
+	entries, err := os.ReadDir(group)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pids, err := fs.readPIDs(filepath.Join(group, entry.Name()), true)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pids...)
+	}
+
+	return ret, nil
+}
+
+func formatPIDs(pids []int) string {
+	ret := ""
+	for _, pid := range pids {
+		ret += strconv.Itoa(pid) + "\n"
+	}
+
+	return ret
+}
+
+// Whether the process exists and is not a zombie
+func isAlive(pid int) bool {
+	content, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return false
+	}
+	// State follows the command name in parentheses
+	i := strings.LastIndexByte(string(content), ')')
+	fields := strings.Fields(string(content[i+1:]))
+
+	return len(fields) != 0 && fields[0] != "Z"
+}
+
+// Sends the signal to the process and, since descendants are not
+// tracked, to the process group it leads. Never signals the process
+// group of this process.
+func signalProcess(pid int, sig syscall.Signal) error {
+	if pgid, err := syscall.Getpgid(pid); err == nil && pgid == pid &&
+		pgid != syscall.Getpgrp() {
+		if err := syscall.Kill(-pgid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+		return nil
+	}
+	if err := syscall.Kill(pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+
+	return nil
+}
@@ -5,9 +5,19 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"golang.org/x/term"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/troplet/internal/shared"
 	"github.com/troplet/pkg/proto"
@@ -28,6 +38,14 @@ func (c Config) String() string {
 		"\nCert key         :" + c.CertKeyPath
 }
 
+const (
+	// Backoff between attempts to resume a broken stream
+	attachMinBackoff = 100 * time.Millisecond
+	attachMaxBackoff = 5 * time.Second
+	// Consecutive attempts without receiving anything
+	attachMaxRetries = 10
+)
+
 type Client struct {
 	config     *Config
 	logger     shared.Logger
@@ -71,13 +89,12 @@ func (c *Client) GetJobStatus(jobID string) {
 	c.dumpJobEntries([]*proto.JobEntry{resp.Job})
 }
 
-func (c *Client) LaunchJob(cmd string, args []string) {
+func (c *Client) LaunchJob(req *proto.LaunchJobRequest) {
 	client, err := c.createClient()
 	if err != nil {
 		return
 	}
-	resp, err := client.LaunchJob(context.Background(),
-		&proto.LaunchJobRequest{Command: cmd, Args: args})
+	resp, err := client.LaunchJob(context.Background(), req)
 	if err != nil {
 		c.logger.Errorf("Failed launching job: %v", err)
 		return
@@ -85,29 +102,230 @@ func (c *Client) LaunchJob(cmd string, args []string) {
 	fmt.Printf("Job ID: %s\n", resp.Id)
 }
 
-func (c *Client) TerminateJob(jobID string) {
+// Terminates the job. Nil grace period applies the server default.
+func (c *Client) TerminateJob(jobID string, gracePeriod *time.Duration) {
 	client, err := c.createClient()
 	if err != nil {
 		return
 	}
-	_, err = client.TerminateJob(context.Background(),
-		&proto.TerminateJobRequest{Id: jobID})
+	req := &proto.TerminateJobRequest{Id: jobID}
+	if gracePeriod != nil {
+		req.GracePeriod = durationpb.New(*gracePeriod)
+	}
+	_, err = client.TerminateJob(context.Background(), req)
 	if err != nil {
 		c.logger.Errorf("Failed terminating job: %v", err)
 	}
 }
 
-func (c *Client) AttachJob(jobID string) {
+func (c *Client) SignalJob(jobID string, signal string) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	_, err = client.SignalJob(context.Background(),
+		&proto.SignalJobRequest{Id: jobID, Signal: signal})
+	if err != nil {
+		c.logger.Errorf("Failed signaling job: %v", err)
+	}
+}
+
+func (c *Client) PauseJob(jobID string) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	_, err = client.PauseJob(context.Background(), &proto.PauseJobRequest{Id: jobID})
+	if err != nil {
+		c.logger.Errorf("Failed pausing job: %v", err)
+	}
+}
+
+func (c *Client) ResumeJob(jobID string) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	_, err = client.ResumeJob(context.Background(), &proto.ResumeJobRequest{Id: jobID})
+	if err != nil {
+		c.logger.Errorf("Failed resuming job: %v", err)
+	}
+}
+
+// Streams output of the job from given offset. If the stream breaks
+// before the job terminates, the client reconnects with backoff and
+// resumes after the last received entry.
+func (c *Client) AttachJob(jobID string, offset int64) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	c.attachJob(client, jobID, offset)
+}
+
+func (c *Client) attachJob(client proto.JobServiceClient, jobID string, offset int64) {
+	// Sequence of the last received entry
+	var lastSequence uint64
+	backoff := attachMinBackoff
+	retries := 0
+	for {
+		req := &proto.AttachJobRequest{Id: jobID, Offset: offset}
+		if lastSequence != 0 {
+			req.FromSequence = lastSequence + 1
+		}
+		received, err := c.attachJobOnce(client, req, &lastSequence)
+		if err == nil {
+			return
+		}
+		if received {
+			retries, backoff = 0, attachMinBackoff
+		}
+		if !isRetriable(err) || retries >= attachMaxRetries {
+			c.logger.Errorf("Server returned error: %v", err)
+			return
+		}
+		retries++
+		c.logger.Warnf("Stream broken, resuming in %s: %v", backoff, err)
+		time.Sleep(backoff)
+		backoff = min(2*backoff, attachMaxBackoff)
+	}
+}
+
+// Streams the job output till the end. Returns if any entry was received
+// and nil error once the job output ended.
+func (c *Client) attachJobOnce(client proto.JobServiceClient, req *proto.AttachJobRequest,
+	lastSequence *uint64) (bool, error) {
+	stream, err := client.AttachJob(context.Background(), req)
+	if err != nil {
+		return false, err
+	}
+	received := false
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return received, err
+		}
+		received = true
+		sequence := response.StreamEntry.Sequence
+		if sequence != 0 {
+			// Skip the entries already printed
+			if sequence <= *lastSequence {
+				continue
+			}
+			*lastSequence = sequence
+		}
+		printStreamEntry(response.StreamEntry)
+	}
+}
+
+// Streams output of the job from given offset while forwarding
+// stdin to the job. EOF of stdin closes stdin of the job. The stream
+// is not resumed if broken since stdin in flight may be lost.
+func (c *Client) InteractJob(jobID string, offset int64, stdin io.Reader) {
 	client, err := c.createClient()
 	if err != nil {
 		return
 	}
-	stream, err := client.AttachJob(context.Background(),
-		&proto.AttachJobRequest{Id: jobID})
+	c.interactJob(client, jobID, offset, stdin, nil)
+}
+
+// Launches the job and streams its output till it terminates. Local
+// stdin is forwarded if the job has stdin open or a terminal. With
+// terminal, the local terminal is put in raw mode and its window size
+// changes are forwarded to the job.
+func (c *Client) ExecJob(req *proto.LaunchJobRequest) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	stdinFD := int(os.Stdin.Fd())
+	isTerminal := req.Tty && term.IsTerminal(stdinFD)
+	if isTerminal {
+		if cols, rows, err := term.GetSize(stdinFD); err == nil {
+			req.TerminalSize = &proto.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)}
+		}
+	}
+	resp, err := client.LaunchJob(context.Background(), req)
+	if err != nil {
+		c.logger.Errorf("Failed launching job: %v", err)
+		return
+	}
+	if !req.OpenStdin && !req.Tty {
+		c.attachJob(client, resp.Id, 0)
+		return
+	}
+	var resizeChan chan *proto.TerminalSize
+	if isTerminal {
+		oldState, err := term.MakeRaw(stdinFD)
+		if err != nil {
+			c.logger.Errorf("Failed setting terminal raw mode: %v", err)
+			return
+		}
+		defer term.Restore(stdinFD, oldState)
+		resizeChan = make(chan *proto.TerminalSize, 1)
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(sigChan)
+			close(sigChan)
+		}()
+		go func() {
+			defer close(resizeChan)
+			for range sigChan {
+				cols, rows, err := term.GetSize(stdinFD)
+				if err != nil {
+					continue
+				}
+				// Only the latest size matters
+				select {
+				case <-resizeChan:
+				default:
+				}
+				resizeChan <- &proto.TerminalSize{Rows: uint32(rows), Cols: uint32(cols)}
+			}
+		}()
+	}
+	c.interactJob(client, resp.Id, 0, os.Stdin, resizeChan)
+}
+
+func (c *Client) interactJob(client proto.JobServiceClient, jobID string, offset int64,
+	stdin io.Reader, resizeChan <-chan *proto.TerminalSize) {
+	stream, err := client.InteractJob(context.Background())
 	if err != nil {
 		c.logger.Errorf("Failed attaching job: %v", err)
 		return
 	}
+	// Stdin and resize requests are sent from separate go routines.
+	// Nil request closes the sending side of the stream.
+	var sendLock sync.Mutex
+	send := func(req *proto.InteractJobRequest) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		if req == nil {
+			return stream.CloseSend()
+		}
+		return stream.Send(req)
+	}
+	if err := send(&proto.InteractJobRequest{
+		Request: &proto.InteractJobRequest_Attach{
+			Attach: &proto.AttachJobRequest{Id: jobID, Offset: offset}}}); err != nil {
+		c.logger.Errorf("Failed attaching job: %v", err)
+		return
+	}
+	go c.sendStdin(send, stdin)
+	if resizeChan != nil {
+		go func() {
+			for size := range resizeChan {
+				if err := send(&proto.InteractJobRequest{
+					Request: &proto.InteractJobRequest_Resize{Resize: size}}); err != nil {
+					return
+				}
+			}
+		}()
+	}
 	for {
 		response, err := stream.Recv()
 		if err != nil {
@@ -116,15 +334,58 @@ func (c *Client) AttachJob(jobID string) {
 			}
 			return
 		}
-		if !response.StreamEntry.IsStdError {
-			fmt.Print(string(response.StreamEntry.Entry))
-		} else {
-			// Print std errors in red
-			fmt.Print("\033[31m" + string(response.StreamEntry.Entry) + "\033[0m")
+		printStreamEntry(response.StreamEntry)
+	}
+}
+
+// Sends stdin till EOF followed by stdin EOF request
+func (c *Client) sendStdin(send func(*proto.InteractJobRequest) error, stdin io.Reader) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := stdin.Read(buf)
+		if n != 0 {
+			if err := send(&proto.InteractJobRequest{
+				Request: &proto.InteractJobRequest_Stdin{Stdin: buf[:n]}}); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				c.logger.Errorf("Failed reading stdin: %v", err)
+			}
+			if err := send(&proto.InteractJobRequest{
+				Request: &proto.InteractJobRequest_StdinEof{StdinEof: true}}); err != nil {
+				return
+			}
+			// Resize requests are not sent after EOF
+			send(nil)
+			return
 		}
 	}
 }
 
+func printStreamEntry(entry *proto.JobStreamEntry) {
+	if entry.DroppedBytes != 0 {
+		// Print gaps in red as well
+		fmt.Printf("\033[31m\n[%d bytes dropped]\n\033[0m", entry.DroppedBytes)
+	} else if !entry.IsStdError {
+		fmt.Print(string(entry.Entry))
+	} else {
+		// Print std errors in red
+		fmt.Print("\033[31m" + string(entry.Entry) + "\033[0m")
+	}
+}
+
+// Returns true if the stream can be resumed after the error
+func isRetriable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+
+	return false
+}
+
 func (c *Client) dumpJobEntries(entries []*proto.JobEntry) {
 	for _, entry := range entries {
 		fmt.Printf("\n")
@@ -132,12 +393,152 @@ func (c *Client) dumpJobEntries(entries []*proto.JobEntry) {
 		fmt.Printf("Command    : %s\n", entry.Command)
 		fmt.Printf("Args       : %s\n", entry.Args)
 		fmt.Printf("Start time : %s\n", entry.StartTs.AsTime().String())
+		if entry.NetworkMode != proto.NetworkMode_NETWORK_MODE_UNSPECIFIED {
+			fmt.Printf("Network    : %s\n", getNetworkModeText(entry.NetworkMode))
+		}
+		if entry.IpAddress != "" {
+			fmt.Printf("IP address : %s\n", entry.IpAddress)
+		}
+		if len(entry.Egress) != 0 {
+			fmt.Printf("Egress     : %s\n", getEgressText(entry.Egress))
+		}
+		if entry.Paused {
+			fmt.Printf("Paused     : yes\n")
+		}
+		if entry.OutputDroppedBytes != 0 {
+			fmt.Printf("Dropped    : %d bytes\n", entry.OutputDroppedBytes)
+		}
+		if limits := entry.Limits; limits != nil {
+			fmt.Printf("CPU        : %dms/%dms\n", limits.CpuQuotaMs, limits.CpuPeriodMs)
+			if limits.CpuWeight != 0 {
+				fmt.Printf("CPU weight : %d\n", limits.CpuWeight)
+			}
+			if limits.CpusetCpus != "" || limits.CpusetMems != "" {
+				fmt.Printf("Pinned     : cpus %q, mems %q\n", limits.CpusetCpus,
+					limits.CpusetMems)
+			}
+			fmt.Printf("Memory     : %dKB\n", limits.MemoryKb)
+			if limits.MemoryHighKb != 0 {
+				fmt.Printf("Memory high: %dKB\n", limits.MemoryHighKb)
+			}
+			if limits.MemoryLowKb != 0 || limits.MemoryMinKb != 0 {
+				fmt.Printf("Protected  : low %dKB, min %dKB\n",
+					limits.MemoryLowKb, limits.MemoryMinKb)
+			}
+			if limits.MemorySwapMaxKb != nil {
+				fmt.Printf("Swap       : %dKB\n", limits.GetMemorySwapMaxKb())
+			}
+			if limits.MemoryOomGroup {
+				fmt.Printf("OOM group  : yes\n")
+			}
+			fmt.Printf("Read bps   : %d\n", limits.ReadBps)
+			fmt.Printf("Write bps  : %d\n", limits.WriteBps)
+			fmt.Printf("IOPS       : read %d, write %d\n", limits.ReadIops, limits.WriteIops)
+			if limits.IoWeight != 0 {
+				fmt.Printf("IO weight  : %d\n", limits.IoWeight)
+			}
+			for _, d := range limits.IoDevices {
+				fmt.Printf("IO %d:%d     : rbps %d, wbps %d, riops %d, wiops %d, weight %d\n",
+					d.DeviceMajorNum, d.DeviceMinorNum, d.ReadBps, d.WriteBps,
+					d.ReadIops, d.WriteIops, d.Weight)
+			}
+			fmt.Printf("Max pids   : %d\n", limits.MaxPids)
+		}
+		if entry.Timeout != nil {
+			fmt.Printf("Timeout    : %s\n", entry.Timeout.AsDuration())
+		}
 		if entry.EndTs.AsTime().After(entry.StartTs.AsTime()) {
 			fmt.Printf("End time   : %s\n", entry.EndTs.AsTime().String())
-			fmt.Printf("Exit error : %s\n", entry.ExitError)
-			fmt.Printf("Exit code  : %d\n", entry.ExitCode)
+			fmt.Printf("Exit error : %s\n", entry.GetExitError())
+			fmt.Printf("Exit code  : %d\n", entry.GetExitCode())
+			if reason := getTerminationReasonText(entry.TerminationReason); reason != "" {
+				fmt.Printf("Reason     : %s\n", reason)
+			}
+			if stats := entry.Stats; stats != nil {
+				fmt.Printf("CPU used   : %s\n",
+					time.Duration(stats.CpuUsageUsec)*time.Microsecond)
+				fmt.Printf("Peak memory: %s\n", formatBytes(stats.MemoryPeakBytes))
+				if stats.MemoryMaxEvents != 0 || stats.MemoryOomKillEvents != 0 {
+					fmt.Printf("Memory max : reached %d times, %d OOM kills\n",
+						stats.MemoryMaxEvents, stats.MemoryOomKillEvents)
+				}
+				if stats.PidsMaxEvents != 0 {
+					fmt.Printf("Pids max   : reached %d times\n", stats.PidsMaxEvents)
+				}
+				if stats.CpuThrottledPeriods != 0 {
+					fmt.Printf("Throttled  : %d/%d periods\n",
+						stats.CpuThrottledPeriods, stats.CpuPeriods)
+				}
+			}
+			if entry.TimedOut {
+				fmt.Printf("Timed out  : yes\n")
+			}
+			switch entry.StopOutcome {
+			case proto.StopOutcome_STOP_OUTCOME_GRACEFUL:
+				fmt.Printf("Stopped    : gracefully\n")
+			case proto.StopOutcome_STOP_OUTCOME_KILLED:
+				fmt.Printf("Stopped    : killed after grace period\n")
+			}
+		}
+		for _, event := range entry.Events {
+			fmt.Printf("Event      : %s %s", event.Ts.AsTime().Format(time.TimeOnly),
+				event.Message)
+			if event.Count > 1 {
+				fmt.Printf(", %d times till %s", event.Count,
+					event.LastTs.AsTime().Format(time.TimeOnly))
+			}
+			fmt.Printf("\n")
+		}
+	}
+}
+
+func getTerminationReasonText(reason proto.TerminationReason) string {
+	switch reason {
+	case proto.TerminationReason_TERMINATION_REASON_EXITED:
+		return "exited"
+	case proto.TerminationReason_TERMINATION_REASON_SIGNALED:
+		return "killed by signal"
+	case proto.TerminationReason_TERMINATION_REASON_OOM_KILLED:
+		return "killed for exceeding memory limit (OOM)"
+	case proto.TerminationReason_TERMINATION_REASON_TIMED_OUT:
+		return "timed out"
+	case proto.TerminationReason_TERMINATION_REASON_TERMINATED_BY_USER:
+		return "terminated by user"
+	case proto.TerminationReason_TERMINATION_REASON_FAILED_TO_START:
+		return "failed to start"
+	case proto.TerminationReason_TERMINATION_REASON_SERVER_SHUTDOWN:
+		return "server shutdown"
+	}
+
+	return ""
+}
+
+func getNetworkModeText(mode proto.NetworkMode) string {
+	switch mode {
+	case proto.NetworkMode_NETWORK_MODE_NONE:
+		return "none"
+	case proto.NetworkMode_NETWORK_MODE_LOOPBACK:
+		return "loopback only"
+	case proto.NetworkMode_NETWORK_MODE_BRIDGED:
+		return "bridged"
+	case proto.NetworkMode_NETWORK_MODE_NAT:
+		return "bridged with NAT"
+	}
+
+	return ""
+}
+
+func getEgressText(egress []*proto.EgressRule) string {
+	rules := []string{}
+	for _, rule := range egress {
+		text := rule.Cidr
+		if len(rule.Ports) != 0 {
+			text += fmt.Sprintf(" ports %v", rule.Ports)
 		}
+		rules = append(rules, text)
 	}
+
+	return strings.Join(rules, ", ")
 }
 
 func (c *Client) createClient() (proto.JobServiceClient, error) {
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/troplet/pkg/proto"
+)
+
+func (c *Client) GetJobStats(jobID string) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	resp, err := client.GetJobStats(context.Background(),
+		&proto.GetJobStatsRequest{Id: jobID})
+	if err != nil {
+		c.logger.Errorf("Failed getting job stats: %v", err)
+		return
+	}
+	printStats(resp.Stats)
+}
+
+// Prints resource usage of the job sampled at given interval
+// till the job terminates
+func (c *Client) WatchJobStats(jobID string, interval time.Duration) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	stream, err := client.WatchJobStats(context.Background(),
+		&proto.WatchJobStatsRequest{Id: jobID, Interval: durationpb.New(interval)})
+	if err != nil {
+		c.logger.Errorf("Failed watching job stats: %v", err)
+		return
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			c.logger.Errorf("Failed watching job stats: %v", err)
+			return
+		}
+		printStats(resp.Stats)
+	}
+}
+
+// Shows resource usage of all the running jobs refreshed at
+// given interval, till interrupted
+func (c *Client) Top(interval time.Duration) {
+	client, err := c.createClient()
+	if err != nil {
+		return
+	}
+	// Previous samples to get CPU usage over the interval
+	previous := map[string]*proto.JobStats{}
+	for {
+		resp, err := client.ListJobs(context.Background(), &proto.ListJobsRequest{})
+		if err != nil {
+			c.logger.Errorf("Failed getting jobs list: %v", err)
+			return
+		}
+		type row struct {
+			entry *proto.JobEntry
+			stats *proto.JobStats
+			cpu   float64
+		}
+		rows := []row{}
+		current := map[string]*proto.JobStats{}
+		for _, entry := range resp.Jobs {
+			if entry.EndTs != nil {
+				continue
+			}
+			statsResp, err := client.GetJobStats(context.Background(),
+				&proto.GetJobStatsRequest{Id: entry.Id})
+			if err != nil {
+				// Terminated meanwhile
+				continue
+			}
+			stats := statsResp.Stats
+			current[entry.Id] = stats
+			r := row{entry: entry, stats: stats}
+			if prev, found := previous[entry.Id]; found {
+				elapsed := stats.Ts.AsTime().Sub(prev.Ts.AsTime())
+				if elapsed > 0 && stats.CpuUsageUsec >= prev.CpuUsageUsec {
+					r.cpu = float64(stats.CpuUsageUsec-prev.CpuUsageUsec) /
+						float64(elapsed.Microseconds()) * 100
+				}
+			}
+			rows = append(rows, r)
+		}
+		previous = current
+		sort.Slice(rows, func(i, j int) bool { return rows[i].cpu > rows[j].cpu })
+		// Clear the screen and move to the top
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("%-36s %6s %10s %10s %5s %s\n",
+			"JOB ID", "CPU%", "MEMORY", "PEAK", "PIDS", "COMMAND")
+		for _, r := range rows {
+			status := ""
+			if r.entry.Paused {
+				status = " (paused)"
+			}
+			fmt.Printf("%-36s %6.1f %10s %10s %5d %s %v%s\n", r.entry.Id, r.cpu,
+				formatBytes(r.stats.MemoryCurrentBytes), formatBytes(r.stats.MemoryPeakBytes),
+				r.stats.PidsCurrent, r.entry.Command, r.entry.Args, status)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func printStats(stats *proto.JobStats) {
+	fmt.Printf("\n")
+	fmt.Printf("Time       : %s\n", stats.Ts.AsTime().String())
+	fmt.Printf("CPU usage  : %s (user %s, system %s)\n",
+		time.Duration(stats.CpuUsageUsec)*time.Microsecond,
+		time.Duration(stats.CpuUserUsec)*time.Microsecond,
+		time.Duration(stats.CpuSystemUsec)*time.Microsecond)
+	fmt.Printf("Throttled  : %d/%d periods, %s\n", stats.CpuThrottledPeriods,
+		stats.CpuPeriods, time.Duration(stats.CpuThrottledUsec)*time.Microsecond)
+	fmt.Printf("Memory     : %s (peak %s)\n", formatBytes(stats.MemoryCurrentBytes),
+		formatBytes(stats.MemoryPeakBytes))
+	fmt.Printf("Memory max : reached %d times, %d OOM, %d OOM kills\n",
+		stats.MemoryMaxEvents, stats.MemoryOomEvents, stats.MemoryOomKillEvents)
+	if len(stats.MemoryStat) != 0 {
+		fmt.Printf("Memory use : anon %s, file %s, kernel %s\n",
+			formatBytes(stats.MemoryStat["anon"]), formatBytes(stats.MemoryStat["file"]),
+			formatBytes(stats.MemoryStat["kernel"]))
+	}
+	for _, io := range stats.Io {
+		fmt.Printf("IO %d:%d     : read %s in %d ops, write %s in %d ops\n",
+			io.DeviceMajorNum, io.DeviceMinorNum, formatBytes(io.ReadBytes), io.ReadIos,
+			formatBytes(io.WriteBytes), io.WriteIos)
+	}
+	fmt.Printf("Processes  : %d, max reached %d times\n", stats.PidsCurrent,
+		stats.PidsMaxEvents)
+	for _, v := range []struct {
+		name     string
+		pressure *proto.PressureStats
+	}{
+		{"CPU", stats.CpuPressure},
+		{"Memory", stats.MemoryPressure},
+		{"IO", stats.IoPressure},
+	} {
+		if v.pressure == nil {
+			continue
+		}
+		fmt.Printf("%-6s PSI : some %.2f%% %.2f%% %.2f%%, full %.2f%% %.2f%% %.2f%%\n",
+			v.name, v.pressure.SomeAvg10, v.pressure.SomeAvg60, v.pressure.SomeAvg300,
+			v.pressure.FullAvg10, v.pressure.FullAvg60, v.pressure.FullAvg300)
+	}
+}
+
+// Formats byte count in binary units
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
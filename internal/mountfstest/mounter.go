@@ -0,0 +1,65 @@
+// Package mountfstest provides a fake mounter for tests
+package mountfstest
+
+import (
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// FakeMounter records mounts and unmounts without performing them
+type FakeMounter struct {
+	lock    sync.Mutex
+	mounts  []FakeMount
+	mounted []string
+}
+
+// Mount recorded by FakeMounter
+type FakeMount struct {
+	Source string
+	Target string
+	FSType string
+	Flags  uintptr
+}
+
+func NewFakeMounter() *FakeMounter {
+	return &FakeMounter{}
+}
+
+func (f *FakeMounter) Mount(source, target, fstype string, flags uintptr, data string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.mounts = append(f.mounts, FakeMount{source, target, fstype, flags})
+	f.mounted = append(f.mounted, target)
+
+	return nil
+}
+
+// Fails with EINVAL, as the kernel does, if the target is not mounted
+func (f *FakeMounter) Unmount(target string, flags int) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	i := slices.Index(f.mounted, target)
+	if i == -1 {
+		return syscall.EINVAL
+	}
+	f.mounted = slices.Delete(f.mounted, i, i+1)
+
+	return nil
+}
+
+// Returns all the mounts so far
+func (f *FakeMounter) Mounts() []FakeMount {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return slices.Clone(f.mounts)
+}
+
+// Returns targets mounted and not yet unmounted
+func (f *FakeMounter) Mounted() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return slices.Clone(f.mounted)
+}
@@ -0,0 +1,571 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/troplet/internal/shared"
+	"github.com/troplet/pkg/exec/cgroups"
+	"github.com/troplet/pkg/exec/netns"
+)
+
+type Config struct {
+	// Listener settings, these are not reloaded
+	Address string `yaml:"address"`
+	// Certificates are reloaded on SIGHUP
+	CABundlePath string `yaml:"ca_bundle"`
+	CertPath     string `yaml:"cert"`
+	CertKeyPath  string `yaml:"cert_key"`
+	// One of debug, info, warn or error
+	LogLevel string `yaml:"log_level"`
+	// How the jobs are run, isolated or as plain processes. This
+	// is not reloaded.
+	Executor ExecutorType `yaml:"executor"`
+	// User the jobs of the process executor run as, by name or uid,
+	// along with its primary group. Required when the server runs as
+	// root. This is not reloaded.
+	ProcessUser string `yaml:"process_user"`
+	// Directory under which new roots of the jobs are created
+	RootBase string `yaml:"root_base"`
+	// Network the isolated jobs are connected to and the network
+	// policies of the clients. Only the policies are reloaded.
+	Network NetworkConfig `yaml:"network"`
+	// Parent control group of the jobs, relative to cgroup2 mount or
+	// absolute under it. Jobs are created under the mount root if
+	// empty. This is not reloaded.
+	CGroupParent string `yaml:"cgroup_parent"`
+	// Fail startup if any of cpu, cpuset, memory, io and pids controllers
+	// cannot be enabled for the jobs. Otherwise the limits of missing
+	// controllers are skipped with a warning. This is not reloaded.
+	CGroupStrict bool `yaml:"cgroup_strict"`
+	// Capacity of the channel used to attach or detach job streams
+	ControlChanCapacity int `yaml:"control_chan_capacity"`
+	// Maximum number of running jobs per client, zero means no limit
+	MaxRunningJobsPerClient int `yaml:"max_running_jobs_per_client"`
+	// Terminated jobs kept per client along with their output, the
+	// oldest ones are purged beyond this. Zero means no limit.
+	MaxTerminatedJobsPerClient int `yaml:"max_terminated_jobs_per_client"`
+	// Journal file persisting job history, kept in memory only
+	// if empty. This is not reloaded.
+	JobStorePath string `yaml:"job_store"`
+	// Directory under which output of every job is persisted
+	OutputDir string `yaml:"output_dir"`
+	// Size cap of persisted output per job. The output is rotated
+	// in segments and the oldest segment is dropped once exceeded.
+	OutputMaxBytes    int64 `yaml:"output_max_bytes"`
+	OutputMaxSegments int   `yaml:"output_max_segments"`
+	// Live output entries buffered per attached client
+	SubscriberBufferEntries int `yaml:"subscriber_buffer_entries"`
+	// Policy once the buffer of a client is full. One of
+	// drop-oldest, disconnect or backpressure.
+	SlowSubscriberPolicy SlowSubscriberPolicy `yaml:"slow_subscriber_policy"`
+	// Address to serve expvar metrics on, disabled if empty.
+	// This is not reloaded.
+	MetricsAddress string `yaml:"metrics_address"`
+	// Time a job gets to exit after SIGTERM before it is killed when
+	// terminated without grace period, or when the server shuts down
+	StopGracePeriod time.Duration `yaml:"stop_grace_period"`
+	// Ceiling for the grace period requested by clients
+	MaxStopGracePeriod time.Duration `yaml:"max_stop_grace_period"`
+	// Timeout applied when a launch request does not specify one,
+	// zero means no timeout, which is allowed only without ceiling
+	DefaultTimeout time.Duration `yaml:"default_timeout"`
+	// Ceiling for the timeout requested by clients, zero means no ceiling
+	MaxTimeout time.Duration `yaml:"max_timeout"`
+	// Limits applied when a launch request does not specify them
+	DefaultLimits LimitsConfig `yaml:"default_limits"`
+	// Ceilings for the limits requested by clients
+	MaxLimits LimitsConfig `yaml:"max_limits"`
+	// Pressure stall thresholds raising events on the jobs, none
+	// if empty
+	PressureAlerts []PressureAlertConfig `yaml:"pressure_alerts"`
+}
+
+type ExecutorType string
+
+// Where cgroup2 is mounted, directly or in a subdirectory
+const cgroupMountBase = "/sys/fs/cgroup"
+
+const (
+	// Jobs run in their own namespaces, root and control group
+	// with the limits applied, which needs root
+	IsolatedExecutor ExecutorType = "isolated"
+	// Jobs run as plain processes of the process user, or of the
+	// server user unless root, without any isolation or limits, such
+	// as for development without root
+	ProcessExecutor ExecutorType = "process"
+)
+
+func (e ExecutorType) Validate() error {
+	switch e {
+	case IsolatedExecutor, ProcessExecutor:
+		return nil
+	}
+
+	return fmt.Errorf("invalid executor %q", e)
+}
+
+type NetworkConfig struct {
+	// Host bridge, created if missing. Jobs have only loopback if empty.
+	Bridge string `yaml:"bridge"`
+	// IPv4 subnet of the bridge such as 10.88.0.0/16. The bridge gets
+	// the first address, which is the default gateway of the jobs.
+	Subnet string `yaml:"subnet"`
+	// Directory persisting addresses leased to the jobs
+	LeaseDir string `yaml:"lease_dir"`
+	// Turn on IPv4 forwarding of the host, needed by jobs in nat
+	// mode. This applies to the whole host and stays on once the
+	// server exits. Jobs in nat mode fail to launch if forwarding
+	// is off.
+	EnableForwarding bool `yaml:"enable_forwarding"`
+	// Policy of the clients without a policy of their own
+	DefaultPolicy NetworkPolicyConfig `yaml:"default_policy"`
+	// Policies by common name of the client certificate
+	ClientPolicies map[string]NetworkPolicyConfig `yaml:"client_policies"`
+}
+
+// Network modes and destinations jobs of a client may use
+type NetworkPolicyConfig struct {
+	// Mode of the jobs launched without one, one of none, loopback,
+	// bridged or nat
+	DefaultMode netns.Mode `yaml:"default_mode"`
+	// Modes clients may request besides the default one
+	Modes []netns.Mode `yaml:"modes"`
+	// Destinations the jobs in bridged or nat mode may send to. Jobs
+	// may request a subset, and can send anywhere if empty.
+	Egress []EgressRuleConfig `yaml:"egress"`
+}
+
+type EgressRuleConfig struct {
+	// IPv4 CIDR such as 10.0.0.0/8
+	CIDR string `yaml:"cidr"`
+	// TCP and UDP ports, every port and protocol if empty
+	Ports []uint16 `yaml:"ports"`
+}
+
+func (n NetworkConfig) validate() error {
+	if err := n.DefaultPolicy.validate(n.Bridge != ""); err != nil {
+		return fmt.Errorf("invalid default policy: %w", err)
+	}
+	for client, policy := range n.ClientPolicies {
+		if err := policy.validate(n.Bridge != ""); err != nil {
+			return fmt.Errorf("invalid policy of %s: %w", client, err)
+		}
+	}
+	if n.Bridge == "" {
+		return nil
+	}
+	// Interface names are limited to 15 characters
+	if len(n.Bridge) > 15 || strings.ContainsAny(n.Bridge, "/ ") {
+		return fmt.Errorf("invalid bridge name %q", n.Bridge)
+	}
+	ip, _, err := net.ParseCIDR(n.Subnet)
+	if err != nil {
+		return fmt.Errorf("invalid subnet: %w", err)
+	}
+	if ip.To4() == nil {
+		return fmt.Errorf("subnet %s is not IPv4", n.Subnet)
+	}
+	if n.LeaseDir == "" {
+		return fmt.Errorf("lease directory must be set")
+	}
+
+	return nil
+}
+
+// Returns network policy of given client
+func (n NetworkConfig) getPolicy(clientID string) *NetworkPolicyConfig {
+	if policy, found := n.ClientPolicies[clientID]; found {
+		return &policy
+	}
+
+	return &n.DefaultPolicy
+}
+
+// Checks the policy, connected modes need a bridge
+func (p NetworkPolicyConfig) validate(hasBridge bool) error {
+	for _, mode := range append([]netns.Mode{p.DefaultMode}, p.Modes...) {
+		if err := mode.Validate(); err != nil {
+			return err
+		}
+		if mode.IsConnected() && !hasBridge {
+			return fmt.Errorf("network mode %s needs a bridge", mode)
+		}
+	}
+	if _, err := p.getEgress(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Checks if the policy allows given mode
+func (p NetworkPolicyConfig) isAllowed(mode netns.Mode) bool {
+	return mode == p.DefaultMode || slices.Contains(p.Modes, mode)
+}
+
+func (p NetworkPolicyConfig) getEgress() ([]netns.EgressRule, error) {
+	rules := []netns.EgressRule{}
+	for _, rule := range p.Egress {
+		egressRule, err := netns.NewEgressRule(rule.CIDR, rule.Ports)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, egressRule)
+	}
+
+	return netns.CompactEgressRules(rules)
+}
+
+func (p NetworkPolicyConfig) String() string {
+	rules, _ := p.getEgress()
+
+	return fmt.Sprintf("default %s, modes %v, egress %v", p.DefaultMode, p.Modes, rules)
+}
+
+type LimitsConfig struct {
+	CPUQuotaMs  int64 `yaml:"cpu_quota_ms"`
+	CPUPeriodMs int64 `yaml:"cpu_period_ms"`
+	MemoryKB    int64 `yaml:"memory_kb"`
+	ReadBps     int64 `yaml:"read_bps"`
+	WriteBps    int64 `yaml:"write_bps"`
+	ReadIOPS    int64 `yaml:"read_iops"`
+	WriteIOPS   int64 `yaml:"write_iops"`
+	MaxPIDs     int64 `yaml:"max_pids"`
+	// Swap is limited only if requested, thus only the ceiling applies
+	MemorySwapKB int64 `yaml:"memory_swap_kb"`
+	// Protection from reclaim is given only if requested, thus only
+	// the ceilings apply. These keep jobs from pinning host memory.
+	MemoryLowKB int64 `yaml:"memory_low_kb"`
+	MemoryMinKB int64 `yaml:"memory_min_kb"`
+}
+
+type PressureAlertConfig struct {
+	// One of cpu, memory or io
+	Resource string `yaml:"resource"`
+	// All the non-idle tasks stalled instead of some
+	Full bool `yaml:"full"`
+	// Stall time within the window that raises the event. The window
+	// must be a multiple of 2s unless the server has CAP_SYS_RESOURCE.
+	Stall  time.Duration `yaml:"stall"`
+	Window time.Duration `yaml:"window"`
+}
+
+func (a PressureAlertConfig) toThreshold() cgroups.PressureThreshold {
+	return cgroups.PressureThreshold{Resource: cgroups.PressureResource(a.Resource),
+		Full: a.Full, Stall: a.Stall, Window: a.Window}
+}
+
+// Returns configuration with all the defaults set
+func DefaultConfig() *Config {
+	certsDir := shared.ServerDefaultCertsDir
+	return &Config{
+		Address:             shared.ServerDefaultListenAddress,
+		CABundlePath:        filepath.Join(certsDir, shared.ServerDefaultCAFile),
+		CertPath:            filepath.Join(certsDir, shared.ServerDefaultCertFile),
+		CertKeyPath:         filepath.Join(certsDir, shared.ServerDefaultCertKeyFile),
+		LogLevel:            "debug",
+		Executor:            IsolatedExecutor,
+		RootBase:            "./",
+		CGroupParent:        "troplet.slice",
+		ControlChanCapacity: 16,
+		OutputDir:           "./data/output",
+		OutputMaxBytes:      16 * 1024 * 1024, // 16MB
+		OutputMaxSegments:   4,
+		Network: NetworkConfig{
+			Subnet:   "10.88.0.0/16",
+			LeaseDir: "./data/leases",
+			DefaultPolicy: NetworkPolicyConfig{
+				DefaultMode: netns.ModeLoopback,
+				Modes:       []netns.Mode{netns.ModeNone},
+			},
+		},
+		// Terminated jobs kept with their output per client
+		MaxTerminatedJobsPerClient: 100,
+		// 256 entries of 128 bytes each
+		SubscriberBufferEntries: 256,
+		SlowSubscriberPolicy:    DropOldestPolicy,
+		StopGracePeriod:         10 * time.Second,
+		MaxStopGracePeriod:      time.Minute,
+		DefaultTimeout:          time.Hour,
+		MaxTimeout:              24 * time.Hour,
+		DefaultLimits: LimitsConfig{
+			CPUQuotaMs:  100,
+			CPUPeriodMs: 1000,
+			MemoryKB:    16 * 1024,       // 16MB
+			ReadBps:     4 * 1024 * 1024, // 4MB
+			WriteBps:    1024 * 1024,     // 1MB
+			ReadIOPS:    1000,
+			WriteIOPS:   500,
+			MaxPIDs:     64,
+		},
+		MaxLimits: LimitsConfig{
+			CPUQuotaMs:   500,
+			CPUPeriodMs:  1000,
+			MemoryKB:     256 * 1024,       // 256MB
+			MemorySwapKB: 256 * 1024,       // 256MB
+			MemoryLowKB:  64 * 1024,        // 64MB
+			MemoryMinKB:  32 * 1024,        // 32MB
+			ReadBps:      16 * 1024 * 1024, // 16MB
+			WriteBps:     4 * 1024 * 1024,  // 4MB
+			ReadIOPS:     10000,
+			WriteIOPS:    5000,
+			MaxPIDs:      1024,
+		},
+		PressureAlerts: []PressureAlertConfig{
+			{Resource: "cpu", Stall: time.Second, Window: 2 * time.Second},
+			{Resource: "memory", Stall: 200 * time.Millisecond, Window: 2 * time.Second},
+			{Resource: "io", Stall: time.Second, Window: 2 * time.Second},
+		},
+	}
+}
+
+// Loads configuration from the given YAML file. Settings missing
+// in the file keep their default values.
+func LoadConfig(path string) (*Config, error) {
+	config := DefaultConfig()
+	content, err := shared.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+func (c Config) String() string {
+	// Must not log sensitive credentials
+	return "\nAddress        :" + c.Address +
+		"\nCA bundle      :" + c.CABundlePath +
+		"\nCert           :" + c.CertPath +
+		"\nCert key       :" + c.CertKeyPath +
+		"\nLog level      :" + c.LogLevel +
+		"\nExecutor       :" + string(c.Executor) +
+		"\nProcess user   :" + c.ProcessUser +
+		"\nRoot base      :" + c.RootBase +
+		"\nNetwork        :" + c.Network.Bridge + " " + c.Network.Subnet +
+		" leases " + c.Network.LeaseDir +
+		" forwarding " + strconv.FormatBool(c.Network.EnableForwarding) +
+		"\nNetwork policy :" + c.Network.DefaultPolicy.String() +
+		c.getClientPoliciesString() +
+		"\nCGroup parent  :" + c.CGroupParent +
+		" strict " + strconv.FormatBool(c.CGroupStrict) +
+		"\nControl chan   :" + strconv.Itoa(c.ControlChanCapacity) +
+		"\nJobs quota     :" + strconv.Itoa(c.MaxRunningJobsPerClient) +
+		" retained " + strconv.Itoa(c.MaxTerminatedJobsPerClient) +
+		"\nJob store      :" + c.JobStorePath +
+		"\nOutput dir     :" + c.OutputDir +
+		"\nOutput cap     :" + strconv.FormatInt(c.OutputMaxBytes, 10) +
+		"/" + strconv.Itoa(c.OutputMaxSegments) +
+		"\nClient buffer  :" + strconv.Itoa(c.SubscriberBufferEntries) +
+		" " + string(c.SlowSubscriberPolicy) +
+		"\nMetrics        :" + c.MetricsAddress +
+		"\nGrace period   :" + c.StopGracePeriod.String() +
+		"/" + c.MaxStopGracePeriod.String() +
+		"\nTimeout        :" + c.DefaultTimeout.String() +
+		"/" + c.MaxTimeout.String() +
+		"\nDefault limits :" + c.DefaultLimits.String() +
+		"\nMax limits     :" + c.MaxLimits.String() +
+		"\nPressure alerts:" + c.getPressureAlertsString()
+}
+
+func (c Config) getPressureAlertsString() string {
+	alerts := []string{}
+	for _, alert := range c.PressureAlerts {
+		alerts = append(alerts, alert.toThreshold().String())
+	}
+
+	return strings.Join(alerts, ", ")
+}
+
+func (c Config) getClientPoliciesString() string {
+	policies := ""
+	for client, policy := range c.Network.ClientPolicies {
+		policies += "\n  " + client + ": " + policy.String()
+	}
+
+	return policies
+}
+
+func (l LimitsConfig) String() string {
+	return fmt.Sprintf("cpu %dms/%dms, memory %dKB, swap %dKB, low %dKB, min %dKB, "+
+		"rbps %d, wbps %d, riops %d, wiops %d, pids %d", l.CPUQuotaMs, l.CPUPeriodMs,
+		l.MemoryKB, l.MemorySwapKB, l.MemoryLowKB, l.MemoryMinKB, l.ReadBps, l.WriteBps,
+		l.ReadIOPS, l.WriteIOPS, l.MaxPIDs)
+}
+
+// Validates the configuration
+func (c *Config) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("address must be set")
+	}
+	for _, path := range []string{c.CABundlePath, c.CertPath, c.CertKeyPath} {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid certificate path: %w", err)
+		}
+	}
+	if _, err := c.GetLogLevel(); err != nil {
+		return err
+	}
+	if err := c.Executor.Validate(); err != nil {
+		return err
+	}
+	if c.RootBase == "" {
+		return fmt.Errorf("root base must be set")
+	}
+	if err := c.Network.validate(); err != nil {
+		return fmt.Errorf("invalid network: %w", err)
+	}
+	// The actual mount is checked once the parent group is created
+	if filepath.IsAbs(c.CGroupParent) &&
+		!strings.HasPrefix(filepath.Clean(c.CGroupParent), cgroupMountBase+"/") {
+		return fmt.Errorf("cgroup parent %s must be under %s", c.CGroupParent,
+			cgroupMountBase)
+	}
+	if c.ControlChanCapacity <= 0 {
+		return fmt.Errorf("invalid control channel capacity %d", c.ControlChanCapacity)
+	}
+	if c.OutputDir == "" {
+		return fmt.Errorf("output directory must be set")
+	}
+	if c.OutputMaxSegments <= 0 || c.OutputMaxBytes < int64(c.OutputMaxSegments) {
+		return fmt.Errorf("invalid output cap %d bytes in %d segments",
+			c.OutputMaxBytes, c.OutputMaxSegments)
+	}
+	if c.SubscriberBufferEntries <= 0 {
+		return fmt.Errorf("invalid subscriber buffer entries %d", c.SubscriberBufferEntries)
+	}
+	if err := c.SlowSubscriberPolicy.Validate(); err != nil {
+		return err
+	}
+	if c.StopGracePeriod < 0 || c.StopGracePeriod > c.MaxStopGracePeriod {
+		return fmt.Errorf("invalid stop grace period %s, max %s",
+			c.StopGracePeriod, c.MaxStopGracePeriod)
+	}
+	if c.MaxTimeout < 0 {
+		return fmt.Errorf("invalid max timeout %s", c.MaxTimeout)
+	}
+	// No timeout by default would let requests bypass the ceiling
+	if c.DefaultTimeout < 0 || (c.MaxTimeout > 0 &&
+		(c.DefaultTimeout == 0 || c.DefaultTimeout > c.MaxTimeout)) {
+		return fmt.Errorf("invalid default timeout %s, max %s",
+			c.DefaultTimeout, c.MaxTimeout)
+	}
+	if c.MaxRunningJobsPerClient < 0 {
+		return fmt.Errorf("invalid running jobs quota %d", c.MaxRunningJobsPerClient)
+	}
+	if c.MaxTerminatedJobsPerClient < 0 {
+		return fmt.Errorf("invalid terminated jobs retention %d",
+			c.MaxTerminatedJobsPerClient)
+	}
+	if err := c.MaxLimits.validate(); err != nil {
+		return fmt.Errorf("invalid max limits: %w", err)
+	}
+	if err := c.DefaultLimits.validate(); err != nil {
+		return fmt.Errorf("invalid default limits: %w", err)
+	}
+	for _, alert := range c.PressureAlerts {
+		if err := alert.toThreshold().Validate(); err != nil {
+			return fmt.Errorf("invalid pressure alert: %w", err)
+		}
+	}
+	// Defaults must be acceptable as if they were requested
+	if _, err := resolveLimits(nil, &c.DefaultLimits, &c.MaxLimits); err != nil {
+		return fmt.Errorf("default limits exceed max limits: %w", err)
+	}
+
+	return nil
+}
+
+// Restores the settings that are not reloaded to their values at
+// startup, returning the names of those that were changed
+func (c *Config) keepStartupSettings(startup *Config) []string {
+	changed := []string{}
+	for _, v := range []struct {
+		name    string
+		changed bool
+	}{
+		{"address", c.Address != startup.Address},
+		{"executor", c.Executor != startup.Executor},
+		{"process_user", c.ProcessUser != startup.ProcessUser},
+		{"network.bridge", c.Network.Bridge != startup.Network.Bridge},
+		{"network.subnet", c.Network.Subnet != startup.Network.Subnet},
+		{"network.lease_dir", c.Network.LeaseDir != startup.Network.LeaseDir},
+		{"network.enable_forwarding",
+			c.Network.EnableForwarding != startup.Network.EnableForwarding},
+		{"cgroup_parent", c.CGroupParent != startup.CGroupParent},
+		{"cgroup_strict", c.CGroupStrict != startup.CGroupStrict},
+		{"job_store", c.JobStorePath != startup.JobStorePath},
+		{"metrics_address", c.MetricsAddress != startup.MetricsAddress},
+	} {
+		if v.changed {
+			changed = append(changed, v.name)
+		}
+	}
+	c.Address = startup.Address
+	c.Executor = startup.Executor
+	c.ProcessUser = startup.ProcessUser
+	c.Network.Bridge = startup.Network.Bridge
+	c.Network.Subnet = startup.Network.Subnet
+	c.Network.LeaseDir = startup.Network.LeaseDir
+	c.Network.EnableForwarding = startup.Network.EnableForwarding
+	c.CGroupParent = startup.CGroupParent
+	c.CGroupStrict = startup.CGroupStrict
+	c.JobStorePath = startup.JobStorePath
+	c.MetricsAddress = startup.MetricsAddress
+
+	return changed
+}
+
+// Returns parsed log level
+func (c *Config) GetLogLevel() (zapcore.Level, error) {
+	level, err := zapcore.ParseLevel(c.LogLevel)
+	if err != nil {
+		return level, fmt.Errorf("invalid log level %s: %w", c.LogLevel, err)
+	}
+
+	return level, nil
+}
+
+func (l *LimitsConfig) validate() error {
+	// cgroups v2 accepts a period between 1ms and 1s
+	if l.CPUPeriodMs < cgroups.MinCPUPeriodMs || l.CPUPeriodMs > cgroups.MaxCPUPeriodMs {
+		return fmt.Errorf("cpu period %dms out of range [%d, %d]", l.CPUPeriodMs,
+			cgroups.MinCPUPeriodMs, cgroups.MaxCPUPeriodMs)
+	}
+	if l.CPUQuotaMs > cgroups.MaxCPUQuotaMs {
+		return fmt.Errorf("cpu quota %dms exceeds %d", l.CPUQuotaMs, cgroups.MaxCPUQuotaMs)
+	}
+	for _, v := range []struct {
+		name  string
+		value int64
+	}{
+		{"cpu quota", l.CPUQuotaMs},
+		{"memory", l.MemoryKB},
+		{"read bps", l.ReadBps},
+		{"write bps", l.WriteBps},
+		{"read iops", l.ReadIOPS},
+		{"write iops", l.WriteIOPS},
+		{"max pids", l.MaxPIDs},
+	} {
+		if v.value <= 0 {
+			return fmt.Errorf("%s must be positive", v.name)
+		}
+	}
+	if l.MemorySwapKB < 0 || l.MemoryLowKB < 0 || l.MemoryMinKB < 0 {
+		return fmt.Errorf("memory swap, low and min must not be negative")
+	}
+
+	return nil
+}
@@ -2,69 +2,191 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/troplet/internal/shared"
 	"github.com/troplet/pkg/exec"
+	"github.com/troplet/pkg/exec/cgroups"
 	"github.com/troplet/pkg/proto"
 )
 
+// Window size of terminal if not requested
+const (
+	defaultTerminalRows = 24
+	defaultTerminalCols = 80
+)
+
 type ControlChanEntry struct {
 	id         uint64
-	stdoutChan chan *proto.JobStreamEntry
-	stderrChan chan *proto.JobStreamEntry
-	isAdd      bool
+	subscriber *subscriber
+	// Receives sequence of the last entry not sent live
+	startSequenceChan chan uint64
+	isAdd             bool
 }
 
 type JobInfo struct {
-	logger shared.Logger
-	info   proto.JobEntry
-	cmd    *exec.Command
+	logger  shared.Logger
+	metrics *Metrics
+	info    proto.JobEntry
+	cmd     exec.Job
+	// Invoked with a copy of job entry on every state transition
+	onUpdate func(*proto.JobEntry)
 	// Wait group for stdout/stderr read and cmd execute go routines cleanup
 	wg sync.WaitGroup
 	// Control chan to communicate attach or detach of streams
 	controlChan chan *ControlChanEntry
-	// Lock to protect subscriber counter and terminated flag
+	// Persisted output of the job, nil if the job failed to launch
+	outputLog *OutputLog
+	// Stdin of the job, nil unless opened at launch or once closed.
+	// Writers hold the read lock while sending.
+	stdinLock sync.RWMutex
+	stdinChan exec.WriteChannel
+	// Closed along with stdin to unblock pending writers
+	stdinDone      chan struct{}
+	stdinCloseOnce sync.Once
+	// Serializes pause and resume
+	pauseLock sync.Mutex
+	// Lock to protect subscriber counter, terminated and streams done flags
 	lock              sync.RWMutex
 	subscriberCounter uint64
 	isTerminated      bool
+	// Set if the job got stopped by Terminate
+	terminatedByUser bool
+	// Set once all the output has been read and persisted
+	streamsDone bool
 }
 
-func NewJobInfo(logger shared.Logger, cmd string, args []string) *JobInfo {
-	// TODO: configuration candidate
-	jobInfo := &JobInfo{logger: logger, controlChan: make(chan *ControlChanEntry, 16)}
+// Events kept per job, the oldest ones are dropped beyond this
+const maxJobEvents = 64
+
+func NewJobInfo(logger shared.Logger, metrics *Metrics, controlChanCapacity int,
+	cmd string, args []string, onUpdate func(*proto.JobEntry)) *JobInfo {
+	jobInfo := &JobInfo{logger: logger, metrics: metrics, onUpdate: onUpdate,
+		controlChan: make(chan *ControlChanEntry, controlChanCapacity),
+		stdinDone:   make(chan struct{})}
 	jobInfo.info.Command = cmd
 	jobInfo.info.Args = args
 
 	return jobInfo
 }
 
-func (j *JobInfo) Launch(rootBase string, quotaMillSeconds, periodMillSeconds int64,
-	memKB int64, rbps, wbps int64, deviceMajorNum, deviceMinorNum int32) string {
+// Returns job info of a terminated job restored from job store.
+// Its persisted output is loaded from the output directory.
+func NewTerminatedJobInfo(logger shared.Logger, entry *proto.JobEntry,
+	outputDir string) *JobInfo {
+	jobInfo := &JobInfo{logger: logger, metrics: NewMetrics(),
+		isTerminated: true, streamsDone: true,
+		controlChan: make(chan *ControlChanEntry), stdinDone: make(chan struct{})}
+	protobuf.Merge(&jobInfo.info, entry)
+	dir := filepath.Join(outputDir, entry.Id)
+	if _, err := os.Stat(dir); err == nil {
+		// Never written, thus size cap does not matter
+		outputLog, err := NewOutputLog(dir, 1, 1)
+		if err != nil {
+			logger.Errorf("Failed loading output of job %s: %v", entry.Id, err)
+		}
+		jobInfo.outputLog = outputLog
+	}
+
+	return jobInfo
+}
+
+// Launches the job with given executor. The job is stopped as per
+// configured stop policy once the context is done or the timeout, if
+// non zero, expires. Limits are nil for jobs run without limits.
+func (j *JobInfo) Launch(ctx context.Context, config *Config, req *proto.LaunchJobRequest,
+	limits *proto.ResourceLimits, timeout time.Duration, network networkSpec,
+	cgroupParent *cgroups.ParentGroup, ioSpec cgroups.IOSpec, executor exec.Executor) string {
 	cmdOptions := []exec.CommandOption{}
 	stdoutChan, stderrChan := make(exec.ReadChannel), make(exec.ReadChannel)
 	cmdOptions = append(cmdOptions, exec.WithStdoutChan(stdoutChan))
 	cmdOptions = append(cmdOptions, exec.WithStderrChan(stderrChan))
-	cmdOptions = append(cmdOptions, exec.WithNewRootBase(rootBase))
-	cmdOptions = append(cmdOptions, exec.WithCPULimit(quotaMillSeconds, periodMillSeconds))
+	var stdinChan exec.WriteChannel
+	if req.OpenStdin || req.Tty {
+		stdinChan = make(exec.WriteChannel)
+		cmdOptions = append(cmdOptions, exec.WithStdinChan(stdinChan))
+	}
+	if req.Tty {
+		rows, cols := getTerminalSize(req.TerminalSize)
+		cmdOptions = append(cmdOptions, exec.WithPTY(rows, cols))
+	}
+	cmdOptions = append(cmdOptions, exec.WithNewRootBase(config.RootBase))
+	if cgroupParent != nil {
+		cmdOptions = append(cmdOptions, exec.WithControlGroupParent(cgroupParent))
+	}
 	cmdOptions = append(cmdOptions, exec.WithUsePIDNS())
-	cmdOptions = append(cmdOptions, exec.WithUseNetNS())
-	cmdOptions = append(cmdOptions, exec.WithMemoryLimit(memKB))
-	cmdOptions = append(cmdOptions, exec.WithIOLimits(deviceMajorNum, deviceMinorNum, rbps, wbps))
+	cmdOptions = append(cmdOptions, exec.WithNetworkMode(network.mode, network.egress))
+	if limits != nil {
+		cmdOptions = append(cmdOptions, exec.WithCPULimit(limits.CpuQuotaMs,
+			limits.CpuPeriodMs))
+		if limits.CpuWeight != 0 {
+			cmdOptions = append(cmdOptions, exec.WithCPUWeight(limits.CpuWeight))
+		}
+		if limits.CpusetCpus != "" || limits.CpusetMems != "" {
+			cmdOptions = append(cmdOptions, exec.WithCPUSet(limits.CpusetCpus,
+				limits.CpusetMems))
+		}
+		cmdOptions = append(cmdOptions, exec.WithMemorySpec(cgroups.MemorySpec{
+			MaxKB: limits.MemoryKb, HighKB: limits.MemoryHighKb,
+			LowKB: limits.MemoryLowKb, MinKB: limits.MemoryMinKb,
+			SwapMaxKB: limits.MemorySwapMaxKb, OOMGroup: limits.MemoryOomGroup}))
+		cmdOptions = append(cmdOptions, exec.WithPIDsLimit(limits.MaxPids))
+		cmdOptions = append(cmdOptions, exec.WithIOSpec(ioSpec))
+	}
+	if len(config.PressureAlerts) != 0 {
+		thresholds := []cgroups.PressureThreshold{}
+		for _, alert := range config.PressureAlerts {
+			thresholds = append(thresholds, alert.toThreshold())
+		}
+		cmdOptions = append(cmdOptions, exec.WithPressureAlerts(thresholds,
+			j.onPressureStall))
+	}
+	cmdOptions = append(cmdOptions, exec.WithStopPolicy(exec.StopPolicy{
+		Signal: syscall.SIGTERM, GracePeriod: config.StopGracePeriod}))
+	if timeout > 0 {
+		cmdOptions = append(cmdOptions, exec.WithTimeout(timeout))
+		j.info.Timeout = durationpb.New(timeout)
+	}
+	j.info.Limits = limits
+	j.info.NetworkMode = getNetworkModeProto(network.mode)
+	j.info.Egress = getEgressProto(network.egress)
 	j.info.StartTs = timestamppb.New(time.Now())
-	cmd, err := exec.NewCommand(j.info.Command, j.info.Args, cmdOptions...)
+	cmd, err := executor.NewJob(j.info.Command, j.info.Args, cmdOptions...)
 	if err != nil {
 		// Since the initiation of this job failed, we will generate
 		// a unique id to keep details about this launch attempt
-		return j.updateJobEntryOnExit(uuid.New().String(), err.Error(), 1)
+		return j.failLaunch(uuid.New().String(), err)
+	}
+	outputLog, err := NewOutputLog(filepath.Join(config.OutputDir, cmd.GetID()),
+		config.OutputMaxBytes, config.OutputMaxSegments)
+	if err != nil {
+		if err := cmd.Finish(); err != nil {
+			j.logger.Errorf("finish failed: %v", err)
+		}
+		return j.failLaunch(cmd.GetID(), err)
 	}
 	j.cmd = cmd
+	j.outputLog = outputLog
+	j.stdinChan = stdinChan
 	j.info.Id = cmd.GetID()
+	if ip := cmd.GetIP(); ip != nil {
+		j.info.IpAddress = ip.String()
+	}
+	// Record the launch before the job gets a chance to terminate
+	j.notifyUpdate()
 
 	// Prepare reading stdout and stderr streams
 	j.wg.Add(1)
@@ -77,11 +199,25 @@ func (j *JobInfo) Launch(rootBase string, quotaMillSeconds, periodMillSeconds in
 	j.wg.Add(1)
 	go func() {
 		defer j.wg.Done()
-		// This will block till the command completes.
-		// Pass non timeout context.
+		// This will block till the command completes
 		j.logger.Infof("Job: %s is running", j.info.Id)
-		cmd.Execute(context.Background())
+		cmd.Execute(ctx)
 		// Command got terminated here
+		j.CloseStdin()
+		j.lock.Lock()
+		j.info.StopOutcome = toProtoStopOutcome(cmd.GetStopOutcome())
+		j.info.TimedOut = cmd.IsTimedOut()
+		j.info.Paused = false
+		reason := toProtoTerminationReason(cmd.GetTerminationReason(), j.terminatedByUser)
+		// Control group of the job is removed on finish
+		if stats, err := cmd.Stats(); err != nil {
+			if !errors.Is(err, exec.ErrNoControlGroup) {
+				j.logger.Errorf("failed getting final stats: %v", err)
+			}
+		} else {
+			j.info.Stats = toProtoStats(stats)
+		}
+		j.lock.Unlock()
 		var exitError string
 		exitErr, err := cmd.GetExitError()
 		if err != nil {
@@ -93,7 +229,7 @@ func (j *JobInfo) Launch(rootBase string, quotaMillSeconds, periodMillSeconds in
 		if err != nil {
 			j.logger.Errorf("failed getting exit code: %v", err)
 		}
-		j.updateJobEntryOnExit(cmd.GetID(), exitError, exitCode)
+		j.updateJobEntryOnExit(cmd.GetID(), exitError, exitCode, reason)
 		// Finish must be called if command object is created
 		if err := cmd.Finish(); err != nil {
 			j.logger.Errorf("finish failed: %v", err)
@@ -103,150 +239,540 @@ func (j *JobInfo) Launch(rootBase string, quotaMillSeconds, periodMillSeconds in
 	return cmd.GetID()
 }
 
-func (j *JobInfo) Terminate() error {
-	terminateCmd := func() error {
-		j.lock.Lock()
-		defer j.lock.Unlock()
-		if j.isTerminated {
-			return fmt.Errorf("job already terminated")
-		}
-		// cmd can be null if it failed the initialization
-		if j.cmd != nil {
-			if err := j.cmd.Kill(); err != nil {
-				j.logger.Errorf("failed killing job: %v", err)
+// Sends SIGTERM to the job and kills it if it does not exit within
+// the grace period. Blocks till the job has terminated.
+func (j *JobInfo) Terminate(gracePeriod time.Duration) error {
+	j.lock.Lock()
+	if j.isTerminated {
+		j.lock.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "job already terminated")
+	}
+	// Set before stopping so that the exit is attributed to the user,
+	// and rolled back if the job could not be stopped
+	j.isTerminated = true
+	j.terminatedByUser = true
+	cmd := j.cmd
+	j.lock.Unlock()
+	// cmd can be null if it failed the initialization
+	if cmd != nil {
+		j.logger.Infof("Job: %s, stopping with grace period %s", j.info.Id, gracePeriod)
+		if err := cmd.Stop(gracePeriod); err != nil {
+			j.logger.Errorf("failed stopping job: %v", err)
+			j.lock.Lock()
+			// Unless the job exited on its own meanwhile
+			exited := j.info.EndTs != nil
+			if !exited {
+				j.isTerminated = false
+				j.terminatedByUser = false
+			}
+			j.lock.Unlock()
+			if !exited {
+				return status.Errorf(codes.Internal, "failed stopping job %s: %v",
+					j.info.Id, err)
 			}
 		}
-		j.isTerminated = true
+	}
+	j.wg.Wait()
 
-		return nil
+	return nil
+}
+
+// Blocks till the job and its output streams are done
+func (j *JobInfo) Wait() {
+	j.wg.Wait()
+}
+
+func toProtoStopOutcome(outcome exec.StopOutcome) proto.StopOutcome {
+	switch outcome {
+	case exec.StopOutcomeGraceful:
+		return proto.StopOutcome_STOP_OUTCOME_GRACEFUL
+	case exec.StopOutcomeKilled:
+		return proto.StopOutcome_STOP_OUTCOME_KILLED
+	}
+
+	return proto.StopOutcome_STOP_OUTCOME_NONE
+}
+
+// Stopped command is reported as terminated by user only if it
+// was stopped by Terminate, otherwise it was the server shutting down
+func toProtoTerminationReason(reason exec.TerminationReason,
+	terminatedByUser bool) proto.TerminationReason {
+	switch reason {
+	case exec.TerminationReasonExited:
+		return proto.TerminationReason_TERMINATION_REASON_EXITED
+	case exec.TerminationReasonSignaled:
+		return proto.TerminationReason_TERMINATION_REASON_SIGNALED
+	case exec.TerminationReasonOOMKilled:
+		return proto.TerminationReason_TERMINATION_REASON_OOM_KILLED
+	case exec.TerminationReasonTimedOut:
+		return proto.TerminationReason_TERMINATION_REASON_TIMED_OUT
+	case exec.TerminationReasonFailedToStart:
+		return proto.TerminationReason_TERMINATION_REASON_FAILED_TO_START
+	case exec.TerminationReasonStopped:
+		if terminatedByUser {
+			return proto.TerminationReason_TERMINATION_REASON_TERMINATED_BY_USER
+		}
+		return proto.TerminationReason_TERMINATION_REASON_SERVER_SHUTDOWN
+	}
+
+	return proto.TerminationReason_TERMINATION_REASON_UNSPECIFIED
+}
+
+// Sends the signal to the running job
+func (j *JobInfo) Signal(sig syscall.Signal) error {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if j.cmd == nil || j.isTerminated {
+		return status.Errorf(codes.FailedPrecondition, "job %s is not running", j.info.Id)
+	}
+
+	return j.cmd.Signal(sig)
+}
+
+// Returns resource usage of the job. Running job is sampled, and the
+// totals captured at exit are returned once it has exited, in which
+// case final is set.
+func (j *JobInfo) GetStats() (stats *proto.JobStats, final bool, err error) {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if j.info.Stats != nil {
+		return protobuf.Clone(j.info.Stats).(*proto.JobStats), true, nil
+	}
+	if j.cmd == nil || j.info.EndTs != nil {
+		return nil, false, status.Errorf(codes.FailedPrecondition,
+			"no stats available for job %s", j.info.Id)
+	}
+	cmdStats, err := j.cmd.Stats()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return toProtoStats(cmdStats), false, nil
+}
+
+func toProtoStats(stats *cgroups.Stats) *proto.JobStats {
+	ret := &proto.JobStats{Ts: timestamppb.Now(),
+		CpuUsageUsec:        stats.CPUUsageUsec,
+		CpuUserUsec:         stats.CPUUserUsec,
+		CpuSystemUsec:       stats.CPUSystemUsec,
+		CpuPeriods:          stats.CPUPeriods,
+		CpuThrottledPeriods: stats.CPUThrottledPeriods,
+		CpuThrottledUsec:    stats.CPUThrottledUsec,
+		MemoryCurrentBytes:  stats.MemoryCurrent,
+		MemoryPeakBytes:     stats.MemoryPeak,
+		MemoryStat:          stats.MemoryStat,
+		MemoryMaxEvents:     stats.MemoryMaxEvents,
+		MemoryOomEvents:     stats.MemoryOOMEvents,
+		MemoryOomKillEvents: stats.MemoryOOMKillEvents,
+		PidsCurrent:         stats.PidsCurrent,
+		PidsMaxEvents:       stats.PidsMaxEvents,
+		CpuPressure:         toProtoPressure(stats.CPUPressure),
+		MemoryPressure:      toProtoPressure(stats.MemoryPressure),
+		IoPressure:          toProtoPressure(stats.IOPressure),
+	}
+	for _, io := range stats.IO {
+		ret.Io = append(ret.Io, &proto.IOStats{
+			DeviceMajorNum: io.DeviceMajorNum, DeviceMinorNum: io.DeviceMinorNum,
+			ReadBytes: io.ReadBytes, WriteBytes: io.WriteBytes,
+			ReadIos: io.ReadIOs, WriteIos: io.WriteIOs})
 	}
-	if err := terminateCmd(); err != nil {
+
+	return ret
+}
+
+func toProtoPressure(pressure cgroups.Pressure) *proto.PressureStats {
+	return &proto.PressureStats{
+		SomeAvg10: pressure.Some.Avg10, SomeAvg60: pressure.Some.Avg60,
+		SomeAvg300: pressure.Some.Avg300, SomeTotalUsec: pressure.Some.TotalUsec,
+		FullAvg10: pressure.Full.Avg10, FullAvg60: pressure.Full.Avg60,
+		FullAvg300: pressure.Full.Avg300, FullTotalUsec: pressure.Full.TotalUsec,
+	}
+}
+
+// Freezes the running job till it is resumed
+func (j *JobInfo) Pause() error {
+	return j.setPaused(true)
+}
+
+// Resumes the paused job
+func (j *JobInfo) Resume() error {
+	return j.setPaused(false)
+}
+
+func (j *JobInfo) setPaused(paused bool) error {
+	// Waiting for the job to freeze or thaw does not hold the
+	// lock, so that status of the job can be read meanwhile
+	j.pauseLock.Lock()
+	defer j.pauseLock.Unlock()
+	j.lock.RLock()
+	cmd := j.cmd
+	running := cmd != nil && !j.isTerminated
+	j.lock.RUnlock()
+	if !running {
+		return status.Errorf(codes.FailedPrecondition, "job %s is not running", j.info.Id)
+	}
+	var err error
+	if paused {
+		err = cmd.Pause()
+	} else {
+		err = cmd.Resume()
+	}
+	if err != nil {
 		return err
 	}
-	j.wg.Wait()
-	close(j.controlChan)
+	j.lock.Lock()
+	if cmd.IsTerminated() {
+		// Terminated meanwhile, the exit clears the paused flag
+		j.lock.Unlock()
+		return nil
+	}
+	j.info.Paused = paused
+	j.lock.Unlock()
+	j.notifyUpdate()
 
 	return nil
 }
 
-func (j *JobInfo) Attach(stdoutChan, stderrChan chan *proto.JobStreamEntry) (uint64, error) {
+// Records crossing of the pressure threshold as a job event. Crossings
+// recurring within two windows are counted in the same event, so that
+// an event marks the start of a stall.
+func (j *JobInfo) onPressureStall(threshold cgroups.PressureThreshold) {
+	now := time.Now()
+	message := "stalled beyond " + threshold.String()
+	j.lock.Lock()
+	if len(j.info.Events) != 0 {
+		last := j.info.Events[len(j.info.Events)-1]
+		if last.Type == proto.JobEventType_JOB_EVENT_TYPE_PRESSURE_STALL &&
+			last.Message == message &&
+			now.Sub(last.LastTs.AsTime()) <= 2*threshold.Window {
+			last.Count++
+			last.LastTs = timestamppb.New(now)
+			j.lock.Unlock()
+			return
+		}
+	}
+	j.info.Events = append(j.info.Events, &proto.JobEvent{Ts: timestamppb.New(now),
+		Type: proto.JobEventType_JOB_EVENT_TYPE_PRESSURE_STALL, Message: message,
+		Count: 1, LastTs: timestamppb.New(now)})
+	if len(j.info.Events) > maxJobEvents {
+		j.info.Events = j.info.Events[len(j.info.Events)-maxJobEvents:]
+	}
+	j.lock.Unlock()
+	j.logger.Warnf("Job: %s, %s", j.info.Id, message)
+	j.metrics.AddPressureStall(threshold.Resource)
+	j.notifyUpdate()
+}
+
+// Writes data to stdin of the job. Blocks till the job takes it,
+// stdin gets closed or the context is done.
+func (j *JobInfo) WriteStdin(ctx context.Context, data []byte) error {
+	j.stdinLock.RLock()
+	defer j.stdinLock.RUnlock()
+	if j.stdinChan == nil {
+		return status.Errorf(codes.FailedPrecondition, "stdin of job %s is not open", j.info.Id)
+	}
+	select {
+	case j.stdinChan <- data:
+		return nil
+	case <-j.stdinDone:
+		return status.Errorf(codes.FailedPrecondition, "stdin of job %s is closed", j.info.Id)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Closes stdin of the job so that it reads EOF
+func (j *JobInfo) CloseStdin() error {
+	j.stdinCloseOnce.Do(func() { close(j.stdinDone) })
+	j.stdinLock.Lock()
+	defer j.stdinLock.Unlock()
+	if j.stdinChan == nil {
+		return status.Errorf(codes.FailedPrecondition, "stdin of job %s is not open", j.info.Id)
+	}
+	close(j.stdinChan)
+	j.stdinChan = nil
+
+	return nil
+}
+
+// Changes window size of the terminal of the job
+func (j *JobInfo) Resize(size *proto.TerminalSize) error {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	if j.cmd == nil || j.isTerminated {
+		return status.Errorf(codes.FailedPrecondition, "job %s is not running", j.info.Id)
+	}
+	rows, cols := getTerminalSize(size)
+
+	return j.cmd.Resize(rows, cols)
+}
+
+// Returns terminal size within the range of window size,
+// or the default size if not set
+func getTerminalSize(size *proto.TerminalSize) (uint16, uint16) {
+	if size == nil || size.Rows == 0 || size.Cols == 0 {
+		return defaultTerminalRows, defaultTerminalCols
+	}
+
+	return uint16(min(size.Rows, math.MaxUint16)), uint16(min(size.Cols, math.MaxUint16))
+}
+
+// Replays output of the job starting from given sequence, or from
+// given offset if the sequence is zero, and then streams live output
+// until the job terminates, the context is done or send fails. Live
+// output is buffered up to given capacity, the policy decides what
+// happens when the buffer is full. Terminated jobs get only the replay.
+func (j *JobInfo) Attach(ctx context.Context, offset int64, fromSequence uint64,
+	capacity int, policy SlowSubscriberPolicy,
+	send func(*proto.JobStreamEntry) error) (err error) {
+	if fromSequence != 0 {
+		offset = 0
+	}
+	startSequenceChan := make(chan uint64, 1)
+	sub, err := j.subscribe(capacity, policy, startSequenceChan)
+	if err != nil {
+		return err
+	}
+	if sub == nil {
+		if j.outputLog == nil {
+			return nil
+		}
+		return j.replay(offset, fromSequence, j.outputLog.GetSequence(), send)
+	}
+	j.logger.Infof("Job: %s, subscriber %d attached", j.info.Id, sub.id)
+	defer j.logger.Infof("Job: %s, subscriber %d detached", j.info.Id, sub.id)
+	// Stop buffering and remove the subscriber when done
+	defer func() {
+		if detachErr := j.detach(sub); detachErr != nil && err == nil {
+			err = detachErr
+		}
+	}()
+	// Entries till this sequence are not sent live
+	startSequence := <-startSequenceChan
+	if err := j.replay(offset, fromSequence, startSequence, send); err != nil {
+		return err
+	}
+	for {
+		entry, err := sub.next(ctx)
+		if err != nil || entry == nil {
+			return err
+		}
+		if err := send(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// Returns nil subscriber if the job has no live output anymore
+func (j *JobInfo) subscribe(capacity int, policy SlowSubscriberPolicy,
+	startSequenceChan chan uint64) (*subscriber, error) {
 	j.lock.Lock()
 	defer j.lock.Unlock()
-	if j.isTerminated {
-		return 0, fmt.Errorf("job already terminated")
+	if j.streamsDone {
+		return nil, nil
 	}
 	j.subscriberCounter++
+	sub := newSubscriber(j.subscriberCounter, capacity, policy)
 
-	// Send control message to add these streams
+	// Send control message to add the subscriber
 	if len(j.controlChan) < cap(j.controlChan) {
-		j.controlChan <- &ControlChanEntry{id: j.subscriberCounter,
-			stdoutChan: stdoutChan,
-			stderrChan: stderrChan,
-			isAdd:      true}
+		j.controlChan <- &ControlChanEntry{id: sub.id,
+			subscriber:        sub,
+			startSequenceChan: startSequenceChan,
+			isAdd:             true}
 	} else {
-		return 0, fmt.Errorf("reached maximum control channel capacity")
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"reached maximum control channel capacity")
 	}
 
-	return j.subscriberCounter, nil
+	return sub, nil
 }
 
-func (j *JobInfo) Detach(subscriberID uint64) {
+// Returns error if the subscriber could not be removed, in which
+// case the closed subscriber stays till the job terminates
+func (j *JobInfo) detach(sub *subscriber) error {
+	// Closing directly unblocks the job output if it is waiting
+	// on this subscriber
+	sub.close()
 	j.lock.RLock()
 	defer j.lock.RUnlock()
-	if j.isTerminated {
-		return
+	if j.streamsDone {
+		return nil
 	}
-	// Send control message to remove these streams
-	if len(j.controlChan) < cap(j.controlChan) {
-		j.controlChan <- &ControlChanEntry{id: subscriberID, isAdd: false}
-	} else {
-		// TODO: The caller might need to retry
-		j.logger.Errorf("reached maximum control channel capacity")
+	// Send control message to remove the subscriber. Other detaches
+	// share the read lock, so never block on a full channel while
+	// the output fan out waits for the lock.
+	select {
+	case j.controlChan <- &ControlChanEntry{id: sub.id, isAdd: false}:
+		return nil
+	default:
 	}
+	j.logger.Errorf("Job: %s, subscriber %d not removed, reached maximum "+
+		"control channel capacity", j.info.Id, sub.id)
+
+	return status.Errorf(codes.ResourceExhausted,
+		"failed detaching from job %s, reached maximum control channel capacity",
+		j.info.Id)
 }
 
-func (j *JobInfo) GetJobStatus() proto.JobEntry {
+func (j *JobInfo) replay(from int64, fromSequence, toSequence uint64,
+	send func(*proto.JobStreamEntry) error) error {
+	if fromSequence > toSequence {
+		return nil
+	}
+
+	return j.outputLog.Read(from, fromSequence, toSequence, send)
+}
+
+func (j *JobInfo) IsTerminated() bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.isTerminated
+}
+
+// Returns if the job has terminated and all its output is persisted
+func (j *JobInfo) IsDone() bool {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.info.EndTs != nil && j.streamsDone
+}
+
+func (j *JobInfo) getEndTime() time.Time {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	return j.info.EndTs.AsTime()
+}
+
+// Removes persisted output of the job from the output directory
+func (j *JobInfo) RemoveOutput(outputDir string) error {
+	j.lock.RLock()
+	jobID := j.info.Id
+	j.lock.RUnlock()
+	if jobID == "" {
+		return nil
+	}
+
+	return os.RemoveAll(filepath.Join(outputDir, jobID))
+}
+
+func (j *JobInfo) GetJobStatus() *proto.JobEntry {
 	j.lock.RLock()
 	defer j.lock.RUnlock()
 
-	return j.info
+	return protobuf.Clone(&j.info).(*proto.JobEntry)
 }
 
 func (j *JobInfo) readStreams(stdoutChan, stderrChan exec.ReadChannel) {
-	// Local maps to store mapping of subscriber id to subscriber channels.
-	// These are modified only by control channel events under one
+	// Local map to store mapping of subscriber id to subscriber.
+	// This is modified only by control channel events under one
 	// execution context thus there is no issue of concurrent access.
-	stdoutChanMap := map[uint64]chan *proto.JobStreamEntry{}
-	stderrChanMap := map[uint64]chan *proto.JobStreamEntry{}
+	subscriberMap := map[uint64]*subscriber{}
+	handleControl := func(entry *ControlChanEntry) {
+		if entry.isAdd {
+			subscriberMap[entry.id] = entry.subscriber
+			entry.startSequenceChan <- j.outputLog.GetSequence()
+		} else if client, found := subscriberMap[entry.id]; found {
+			client.close()
+			delete(subscriberMap, entry.id)
+		}
+	}
 	numChannels := 2
 	for numChannels > 0 {
+		var data []byte
+		var isStdError bool
 		select {
-		case data, ok := <-stdoutChan:
+		case stdout, ok := <-stdoutChan:
 			if !ok {
 				stdoutChan = nil
 				numChannels--
 				continue
 			}
-			entry := &proto.JobStreamEntry{Entry: data, IsStdError: false}
-			// Send data to all the clients
-			for _, client := range stdoutChanMap {
-				// TODO: This will block if one of the client is
-				// not reading on the channel
-				client <- entry
-			}
-		case data, ok := <-stderrChan:
+			data = stdout
+		case stderr, ok := <-stderrChan:
 			if !ok {
 				stderrChan = nil
 				numChannels--
 				continue
 			}
-			entry := &proto.JobStreamEntry{Entry: data, IsStdError: true}
-			// Send data to all the clients
-			for _, client := range stderrChanMap {
-				// TODO: This will block if one of the client is
-				// not listening on the channel
-				client <- entry
-			}
-		case entry, ok := <-j.controlChan:
-			if !ok {
-				goto done
+			data, isStdError = stderr, true
+		case control := <-j.controlChan:
+			handleControl(control)
+			continue
+		}
+		// Persist before fan out so that the subscribers
+		// attaching later get it as replay. This also assigns
+		// the sequence and stream offset.
+		entry, err := j.outputLog.Write(isStdError, data, time.Now())
+		if err != nil {
+			j.logger.Errorf("Job: %s, failed persisting output: %v", j.info.Id, err)
+		}
+		// Send data to all the clients. This does not block unless
+		// a client with backpressure policy is lagging behind.
+		for id, client := range subscriberMap {
+			dropped := client.push(entry)
+			if dropped == 0 {
+				continue
 			}
-			if entry.isAdd {
-				stdoutChanMap[entry.id] = entry.stdoutChan
-				stderrChanMap[entry.id] = entry.stderrChan
-				j.logger.Infof("Job: %s, subscriber %d attached", j.info.Id, entry.id)
-			} else {
-				if client, found := stdoutChanMap[entry.id]; found {
-					close(client)
-					delete(stdoutChanMap, entry.id)
-					j.logger.Infof("Job: %s, subscriber %d detached", j.info.Id, entry.id)
-				}
-				if client, found := stderrChanMap[entry.id]; found {
-					close(client)
-					delete(stderrChanMap, entry.id)
-				}
+			j.lock.Lock()
+			j.info.OutputDroppedBytes += dropped
+			j.lock.Unlock()
+			j.metrics.DroppedBytes.Add(dropped)
+			if client.policy == DisconnectPolicy {
+				j.metrics.DisconnectedSubscribers.Add(1)
+				delete(subscriberMap, id)
+				j.logger.Warnf("Job: %s, subscriber %d disconnected for being slow",
+					j.info.Id, id)
 			}
 		}
 	}
-done:
-	// The job has terminated.
-	// We can close any subscriber channels we have
-	for _, client := range stdoutChanMap {
-		close(client)
+	if err := j.outputLog.Close(); err != nil {
+		j.logger.Errorf("Job: %s, failed closing output: %v", j.info.Id, err)
+	}
+	// All the output is persisted now. No more control messages
+	// will be sent, serve the ones already queued.
+	j.lock.Lock()
+	j.streamsDone = true
+	j.lock.Unlock()
+	for len(j.controlChan) > 0 {
+		handleControl(<-j.controlChan)
 	}
-	for _, client := range stderrChanMap {
-		close(client)
+	// We can close any subscribers we have
+	for _, client := range subscriberMap {
+		client.close()
 	}
 }
 
+func (j *JobInfo) notifyUpdate() {
+	if j.onUpdate != nil {
+		j.onUpdate(j.GetJobStatus())
+	}
+}
+
+// Records the job as failed to start. It has no output streams,
+// so that attaching to it returns right away.
+func (j *JobInfo) failLaunch(jobID string, err error) string {
+	j.lock.Lock()
+	j.streamsDone = true
+	j.lock.Unlock()
+
+	return j.updateJobEntryOnExit(jobID, err.Error(), 1,
+		proto.TerminationReason_TERMINATION_REASON_FAILED_TO_START)
+}
+
 func (j *JobInfo) updateJobEntryOnExit(jobID string, exitError string,
-	exitCode int) string {
+	exitCode int, reason proto.TerminationReason) string {
+	defer j.notifyUpdate()
 	j.lock.Lock()
 	defer j.lock.Unlock()
 	j.info.Id = jobID
 	j.info.EndTs = timestamppb.New(time.Now())
-	j.info.ExitError = exitError
-	j.info.ExitCode = int32(exitCode)
+	j.info.ExitError = &exitError
+	exitCode32 := int32(exitCode)
+	j.info.ExitCode = &exitCode32
+	j.info.TerminationReason = reason
 	j.isTerminated = true
 	j.logger.Infof("Job: %s has terminated", j.info.Id)
 
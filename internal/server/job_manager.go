@@ -1,118 +1,381 @@
 package server
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"math"
+	"os/user"
+	"slices"
+	"sort"
+	"strconv"
 	"sync"
 	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/troplet/internal/shared"
+	"github.com/troplet/pkg/exec"
+	"github.com/troplet/pkg/exec/cgroups"
+	"github.com/troplet/pkg/exec/mountfs"
+	"github.com/troplet/pkg/exec/netns"
 	"github.com/troplet/pkg/proto"
 )
 
-const (
-	// TODO: Configuration candidates
-	memKB             = 16 * 1024       // 16MB
-	rbps              = 4 * 1024 * 1024 // 4MB
-	wbps              = 1024 * 1024     // 1MB
-	quotaMillSeconds  = 100
-	periodMillSeconds = 1000
-	rootBase          = "./"
-)
-
 type ClientInfo struct {
 	// Map of job id (UUID) to job info
 	jobInfoMap map[string]*JobInfo
+	// Launches counted against the quota before their job is recorded
+	pendingLaunches int
 }
 
 type JobManager struct {
 	logger shared.Logger
 	// Map of client-id to client info
-	clientInfoMap  map[string]*ClientInfo
-	deviceMajorNum int32
-	deviceMinorNum int32
-	// To protect the map
+	clientInfoMap map[string]*ClientInfo
+	// Persists job history
+	store   JobStore
+	metrics *Metrics
+	// Configuration is replaced as a whole on reload
+	config *Config
+	// Disks backing roots of the jobs
+	devices []mountfs.Device
+	// Parent control group of the jobs, nil if jobs are created
+	// under cgroup2 mount root
+	cgroupParent *cgroups.ParentGroup
+	// To protect the map, configuration and devices
 	lock sync.RWMutex
+	// Context of all the jobs, cancelled on shutdown
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+	// Runs the jobs, as per configuration unless set by options
+	executor exec.Executor
+	// Jobs run as plain processes, without limits
+	unisolated bool
+	// Backends of isolated jobs, the real ones if not set
+	cgroupFS cgroups.FS
+	mounter  mountfs.Mounter
 }
 
-func NewJobManager(logger shared.Logger) (*JobManager, error) {
-	mount, err := getFilesystemMount(rootBase)
-	if err != nil {
+// Options of the job manager
+type JobManagerOption func(*JobManager)
+
+// Option to run the jobs with given executor instead of the
+// configured one, such as one with scripted jobs for tests. The
+// jobs get no parent control group.
+func WithExecutor(executor exec.Executor) JobManagerOption {
+	return func(m *JobManager) {
+		m.executor = executor
+	}
+}
+
+// Option to manage control groups of the jobs on given cgroup2
+// filesystem, such as a fake one for unprivileged tests
+func WithControlGroupFS(fs cgroups.FS) JobManagerOption {
+	return func(m *JobManager) {
+		m.cgroupFS = fs
+	}
+}
+
+// Option to prepare roots of the jobs with given mounter, such as
+// a fake one for unprivileged tests
+func WithMounter(mounter mountfs.Mounter) JobManagerOption {
+	return func(m *JobManager) {
+		m.mounter = mounter
+	}
+}
+
+// Exit error of jobs that were running when the server stopped
+const serverRestartExitError = "server-restart"
+
+// Sampling interval of job stats if not requested, and the minimum
+const (
+	defaultStatsInterval = time.Second
+	minStatsInterval     = 100 * time.Millisecond
+)
+
+func NewJobManager(logger shared.Logger, config *Config, store JobStore,
+	metrics *Metrics, options ...JobManagerOption) (*JobManager, error) {
+	m := &JobManager{logger: logger, clientInfoMap: make(map[string]*ClientInfo),
+		store: store, metrics: metrics, config: config}
+	for _, option := range options {
+		option(m)
+	}
+	var err error
+	if m.devices, err = getRootBaseDevices(logger, config.RootBase); err != nil {
 		return nil, err
 	}
-	deviceMajorNum, deviceMinorNum, err := getDeviceNumbers(mount)
-	if err != nil {
+	if m.executor == nil {
+		if m.executor, m.cgroupParent, err = m.newExecutor(); err != nil {
+			return nil, err
+		}
+		m.unisolated = config.Executor == ProcessExecutor
+	}
+	m.jobsCtx, m.cancelJobs = context.WithCancel(context.Background())
+	if err := m.restoreJobs(); err != nil {
 		return nil, err
 	}
-	logger.Infof("Using mount %s, device-major-num: %d, device-minor-num: %d",
-		mount, deviceMajorNum, deviceMinorNum)
 
-	return &JobManager{logger: logger, clientInfoMap: make(map[string]*ClientInfo),
-		deviceMajorNum: deviceMajorNum, deviceMinorNum: deviceMinorNum}, nil
+	return m, nil
 }
 
-func (m *JobManager) Launch(ctx context.Context, clientID string,
-	name string, args []string) string {
-	jobInfo := NewJobInfo(m.logger, name, args)
-	jobID := jobInfo.Launch(rootBase, quotaMillSeconds, periodMillSeconds,
-		memKB, rbps, wbps, m.deviceMajorNum, m.deviceMinorNum)
+// Applies reloaded configuration to the jobs launched from now on.
+// Running jobs are not affected.
+func (m *JobManager) Reload(config *Config) error {
+	m.lock.RLock()
+	devices := m.devices
+	rootBaseChanged := m.config.RootBase != config.RootBase
+	m.lock.RUnlock()
+	if rootBaseChanged {
+		var err error
+		devices, err = getRootBaseDevices(m.logger, config.RootBase)
+		if err != nil {
+			return err
+		}
+	}
 
 	m.lock.Lock()
 	defer m.lock.Unlock()
-	// Any failed or successful job execution needs to be recorded
-	clientInfo, found := m.clientInfoMap[clientID]
-	if !found {
-		clientInfo = &ClientInfo{jobInfoMap: make(map[string]*JobInfo)}
-		m.clientInfoMap[clientID] = clientInfo
+	m.config = config
+	m.devices = devices
+
+	return nil
+}
+
+func (m *JobManager) Launch(ctx context.Context, clientID string,
+	req *proto.LaunchJobRequest) (string, error) {
+	m.lock.RLock()
+	config := m.config
+	devices := m.devices
+	m.lock.RUnlock()
+	limits, err := resolveLimits(req.Limits, &config.DefaultLimits, &config.MaxLimits)
+	if err != nil {
+		return "", toStatus(codes.InvalidArgument, err)
+	}
+	ioSpec, err := getIOSpec(limits, devices)
+	if err != nil {
+		return "", toStatus(codes.InvalidArgument, err)
+	}
+	// Limits are still validated, but neither applied nor reported
+	// for plain processes
+	if m.unisolated {
+		limits = nil
+	}
+	timeout, err := resolveTimeout(req.Timeout, config)
+	if err != nil {
+		return "", toStatus(codes.InvalidArgument, err)
 	}
+	// Plain processes share the network of the host, so no mode
+	// or egress rules apply and none are reported
+	network := networkSpec{}
+	if m.unisolated {
+		if (req.NetworkMode != proto.NetworkMode_NETWORK_MODE_UNSPECIFIED &&
+			req.NetworkMode != proto.NetworkMode_NETWORK_MODE_NONE) || len(req.Egress) != 0 {
+			return "", status.Errorf(codes.FailedPrecondition,
+				"network modes and egress rules are not supported by the process executor")
+		}
+	} else if network, err = resolveNetwork(req, config.Network.getPolicy(clientID)); err != nil {
+		return "", toStatus(codes.InvalidArgument, err)
+	}
+	clientInfo, err := m.reserveLaunch(clientID, config.MaxRunningJobsPerClient)
+	if err != nil {
+		return "", err
+	}
+	jobInfo := NewJobInfo(m.logger, m.metrics, config.ControlChanCapacity,
+		req.Command, req.Args,
+		func(entry *proto.JobEntry) { m.saveJob(clientID, entry) })
+	jobID := jobInfo.Launch(m.jobsCtx, config, req, limits, timeout, network,
+		m.cgroupParent, ioSpec, m.executor)
+
+	m.lock.Lock()
+	// Any failed or successful job execution needs to be recorded,
+	// which takes over the reserved slot
+	clientInfo.pendingLaunches--
 	clientInfo.jobInfoMap[jobID] = jobInfo
+	m.lock.Unlock()
+	m.purgeJobs(clientID)
+
+	return jobID, nil
+}
+
+// Terminates the job giving it the requested grace period to exit,
+// or the configured one if not requested
+func (m *JobManager) Terminate(ctx context.Context, clientID string, jobID string,
+	requested *durationpb.Duration) error {
+	m.lock.RLock()
+	config := m.config
+	m.lock.RUnlock()
+	gracePeriod := config.StopGracePeriod
+	if requested != nil {
+		if err := requested.CheckValid(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid grace period: %v", err)
+		}
+		gracePeriod = requested.AsDuration()
+		if gracePeriod < 0 || gracePeriod > config.MaxStopGracePeriod {
+			return status.Errorf(codes.InvalidArgument,
+				"grace period %s out of range [0, %s]", gracePeriod, config.MaxStopGracePeriod)
+		}
+	}
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+
+	return jobInfo.Terminate(gracePeriod)
+}
 
-	return jobID
+func (m *JobManager) Signal(ctx context.Context, clientID string, jobID string,
+	signal string) error {
+	sig, err := exec.ParseSignal(signal)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	// These would stop or continue the job behind the back of
+	// its paused state
+	switch sig {
+	case syscall.SIGSTOP, syscall.SIGTSTP, syscall.SIGTTIN, syscall.SIGTTOU,
+		syscall.SIGCONT:
+		return status.Errorf(codes.InvalidArgument,
+			"signal %s is not allowed, use pause or resume instead", signal)
+	}
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+
+	return jobInfo.Signal(sig)
 }
 
-func (m *JobManager) Terminate(ctx context.Context, clientID string, jobID string) error {
+func (m *JobManager) GetStats(ctx context.Context, clientID string,
+	jobID string) (*proto.JobStats, error) {
 	jobInfo := m.getJobInfo(clientID, jobID)
 	if jobInfo == nil {
-		return fmt.Errorf("job id %s not found", jobID)
+		return nil, errJobNotFound(jobID)
 	}
+	stats, _, err := jobInfo.GetStats()
 
-	return jobInfo.Terminate()
+	return stats, err
+}
+
+// Samples resource usage of the job at given interval, or the default
+// one if not requested, and calls send for every sample. Blocks till
+// the totals of the terminated job are sent or the context is done.
+func (m *JobManager) WatchStats(ctx context.Context, clientID string, jobID string,
+	requested *durationpb.Duration, send func(*proto.JobStats) error) error {
+	interval := defaultStatsInterval
+	if requested != nil {
+		if err := requested.CheckValid(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid interval: %v", err)
+		}
+		interval = requested.AsDuration()
+		if interval < minStatsInterval {
+			return status.Errorf(codes.InvalidArgument, "interval %s below minimum %s",
+				interval, minStatsInterval)
+		}
+	}
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		stats, final, err := jobInfo.GetStats()
+		if err != nil {
+			return err
+		}
+		if err := send(stats); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (m *JobManager) Pause(ctx context.Context, clientID string, jobID string) error {
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+
+	return jobInfo.Pause()
+}
+
+func (m *JobManager) Resume(ctx context.Context, clientID string, jobID string) error {
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+
+	return jobInfo.Resume()
 }
 
 func (m *JobManager) GetJobStatus(ctx context.Context,
 	clientID string, jobID string) (*proto.JobEntry, error) {
 	jobInfo := m.getJobInfo(clientID, jobID)
 	if jobInfo == nil {
-		return nil, fmt.Errorf("job id %s not found", jobID)
+		return nil, errJobNotFound(jobID)
 	}
-	info := jobInfo.GetJobStatus()
 
-	return &info, nil
+	return jobInfo.GetJobStatus(), nil
 }
 
+// Sends job output from given sequence, or offset if the sequence is
+// zero, followed by live output. Blocks till the job terminates, the
+// context is done or send fails.
 func (m *JobManager) Attach(ctx context.Context, clientID string, jobID string,
-	stdoutChan, stderrChan chan *proto.JobStreamEntry) (uint64, error) {
+	offset int64, fromSequence uint64, send func(*proto.JobStreamEntry) error) error {
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+	m.lock.RLock()
+	config := m.config
+	m.lock.RUnlock()
+
+	return jobInfo.Attach(ctx, offset, fromSequence, config.SubscriberBufferEntries,
+		config.SlowSubscriberPolicy, send)
+}
+
+// Writes data to stdin of the job
+func (m *JobManager) WriteStdin(ctx context.Context, clientID string, jobID string,
+	data []byte) error {
 	jobInfo := m.getJobInfo(clientID, jobID)
 	if jobInfo == nil {
-		return 0, fmt.Errorf("job id %s not found", jobID)
+		return errJobNotFound(jobID)
 	}
 
-	return jobInfo.Attach(stdoutChan, stderrChan)
+	return jobInfo.WriteStdin(ctx, data)
 }
 
-func (m *JobManager) Detach(ctx context.Context, clientID string,
-	jobID string, subscriberID uint64) error {
+// Changes window size of the terminal of the job
+func (m *JobManager) Resize(ctx context.Context, clientID string, jobID string,
+	size *proto.TerminalSize) error {
 	jobInfo := m.getJobInfo(clientID, jobID)
 	if jobInfo == nil {
-		return fmt.Errorf("job id %s not found", jobID)
+		return errJobNotFound(jobID)
 	}
-	jobInfo.Detach(subscriberID)
 
-	return nil
+	return jobInfo.Resize(size)
+}
+
+// Closes stdin of the job
+func (m *JobManager) CloseStdin(ctx context.Context, clientID string, jobID string) error {
+	jobInfo := m.getJobInfo(clientID, jobID)
+	if jobInfo == nil {
+		return errJobNotFound(jobID)
+	}
+
+	return jobInfo.CloseStdin()
 }
 
 func (m *JobManager) GetAllJobStatuses(ctx context.Context,
@@ -124,26 +387,250 @@ func (m *JobManager) GetAllJobStatuses(ctx context.Context,
 		m.lock.RUnlock()
 		return nil
 	}
+	// Jobs get purged in the background, copy them while holding the lock
+	jobInfos := make([]*JobInfo, 0, len(clientInfo.jobInfoMap))
+	for _, jobInfo := range clientInfo.jobInfoMap {
+		jobInfos = append(jobInfos, jobInfo)
+	}
 	m.lock.RUnlock()
 	jobs := []*proto.JobEntry{}
-	for _, jobInfo := range clientInfo.jobInfoMap {
-		jobs = append(jobs, &jobInfo.info)
+	for _, jobInfo := range jobInfos {
+		jobs = append(jobs, jobInfo.GetJobStatus())
 	}
 
 	return jobs
 }
 
-func (m *JobManager) Finish() {
+// Stops all the running jobs as per configured stop policy and
+// waits for them to terminate. Jobs launched afterwards are stopped
+// right after launch.
+func (m *JobManager) Shutdown() {
+	m.cancelJobs()
 	m.lock.RLock()
-	defer m.lock.RUnlock()
-	// Cleanup all the jobs
+	jobInfos := []*JobInfo{}
 	for _, clientInfo := range m.clientInfoMap {
 		for _, jobInfo := range clientInfo.jobInfoMap {
-			jobInfo.Terminate()
+			jobInfos = append(jobInfos, jobInfo)
+		}
+	}
+	m.lock.RUnlock()
+	for _, jobInfo := range jobInfos {
+		jobInfo.Wait()
+	}
+}
+
+func (m *JobManager) Finish() {
+	// Cleanup all the jobs
+	m.Shutdown()
+	if err := m.store.Close(); err != nil {
+		m.logger.Errorf("Failed closing job store: %v", err)
+	}
+}
+
+// Loads job history from the store. Jobs that were still running
+// when the server stopped are recorded as terminated.
+func (m *JobManager) restoreJobs() error {
+	clientEntries, err := m.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed loading job history: %w", err)
+	}
+	count := 0
+	for clientID, entries := range clientEntries {
+		clientInfo := &ClientInfo{jobInfoMap: make(map[string]*JobInfo)}
+		m.clientInfoMap[clientID] = clientInfo
+		for _, entry := range entries {
+			if entry.EndTs == nil {
+				exitError, exitCode := serverRestartExitError, int32(-1)
+				entry.EndTs = timestamppb.Now()
+				entry.ExitError = &exitError
+				entry.ExitCode = &exitCode
+				entry.Paused = false
+				entry.TerminationReason =
+					proto.TerminationReason_TERMINATION_REASON_SERVER_SHUTDOWN
+				m.saveJob(clientID, entry)
+			}
+			clientInfo.jobInfoMap[entry.Id] = NewTerminatedJobInfo(m.logger, entry,
+				m.config.OutputDir)
+			count++
+		}
+		m.purgeJobs(clientID)
+	}
+	m.logger.Infof("Restored %d jobs from history", count)
+
+	return nil
+}
+
+func (m *JobManager) saveJob(clientID string, entry *proto.JobEntry) {
+	if err := m.store.Save(clientID, entry); err != nil {
+		m.logger.Errorf("Failed saving job %s: %v", entry.Id, err)
+	}
+}
+
+// Purges the oldest terminated jobs of the client beyond the
+// configured retention, along with their persisted output
+func (m *JobManager) purgeJobs(clientID string) {
+	m.lock.Lock()
+	config := m.config
+	var purged []*JobInfo
+	if clientInfo, found := m.clientInfoMap[clientID]; found &&
+		config.MaxTerminatedJobsPerClient > 0 {
+		purged = clientInfo.removeOldestTerminated(config.MaxTerminatedJobsPerClient)
+	}
+	m.lock.Unlock()
+	for _, jobInfo := range purged {
+		jobID := jobInfo.GetJobStatus().Id
+		if err := m.store.Delete(clientID, jobID); err != nil {
+			m.logger.Errorf("Failed deleting job %s: %v", jobID, err)
+		}
+		if err := jobInfo.RemoveOutput(config.OutputDir); err != nil {
+			m.logger.Errorf("Failed removing output of job %s: %v", jobID, err)
 		}
+		m.logger.Infof("Purged job %s", jobID)
 	}
 }
 
+// Reserves a slot for a launch of the client within the maximum
+// running jobs, 0 for no maximum. Checked and counted in one
+// critical section so that concurrent launches cannot exceed it.
+func (m *JobManager) reserveLaunch(clientID string, maxRunning int) (*ClientInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	clientInfo, found := m.clientInfoMap[clientID]
+	if !found {
+		clientInfo = &ClientInfo{jobInfoMap: make(map[string]*JobInfo)}
+		m.clientInfoMap[clientID] = clientInfo
+	}
+	if maxRunning > 0 && clientInfo.getRunningJobsCount() >= maxRunning {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"reached maximum of %d running jobs", maxRunning)
+	}
+	clientInfo.pendingLaunches++
+
+	return clientInfo, nil
+}
+
+// Removes and returns the oldest terminated jobs beyond given count
+func (c *ClientInfo) removeOldestTerminated(keep int) []*JobInfo {
+	terminated := []*JobInfo{}
+	for _, jobInfo := range c.jobInfoMap {
+		if jobInfo.IsDone() {
+			terminated = append(terminated, jobInfo)
+		}
+	}
+	if len(terminated) <= keep {
+		return nil
+	}
+	sort.Slice(terminated, func(i, j int) bool {
+		return terminated[i].getEndTime().Before(terminated[j].getEndTime())
+	})
+	purged := terminated[:len(terminated)-keep]
+	for _, jobInfo := range purged {
+		delete(c.jobInfoMap, jobInfo.GetJobStatus().Id)
+	}
+
+	return purged
+}
+
+// Returns jobs of the client still running or being launched
+func (c *ClientInfo) getRunningJobsCount() int {
+	count := c.pendingLaunches
+	for _, jobInfo := range c.jobInfoMap {
+		if !jobInfo.IsTerminated() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Returns executor of the jobs as per configuration, along with the
+// parent control group of isolated jobs
+func (m *JobManager) newExecutor() (exec.Executor, *cgroups.ParentGroup, error) {
+	if m.config.Executor == ProcessExecutor {
+		credential, err := lookupCredential(m.config.ProcessUser)
+		if err != nil {
+			return nil, nil, err
+		}
+		executor, err := exec.NewProcessExecutor(credential)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed creating process executor: %w", err)
+		}
+		m.logger.Warnf("Jobs run as plain processes without isolation or limits")
+		return executor, nil, nil
+	}
+	cgroupParent, err := newCGroupParent(m.logger, m.config, m.cgroupFS)
+	if err != nil {
+		return nil, nil, err
+	}
+	options := []exec.CommandOption{}
+	if m.cgroupFS != nil {
+		options = append(options, exec.WithControlGroupFS(m.cgroupFS))
+	}
+	if m.mounter != nil {
+		options = append(options, exec.WithMounter(m.mounter))
+	}
+	if m.config.Network.Bridge != "" {
+		network, err := netns.NewNetwork(m.config.Network.Bridge, m.config.Network.Subnet,
+			m.config.Network.LeaseDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed creating job network: %w", err)
+		}
+		m.logger.Infof("Jobs connected to bridge %s, subnet %s",
+			network.GetBridgeName(), network.GetSubnet())
+		if m.config.Network.EnableForwarding {
+			enabled, err := netns.EnableForwarding()
+			if err != nil {
+				return nil, nil, err
+			}
+			if enabled {
+				m.logger.Warnf("Turned on IPv4 forwarding of the host for jobs in nat mode")
+			}
+		}
+		options = append(options, exec.WithNetwork(network))
+	}
+
+	return exec.NewIsolatedExecutor(options...), cgroupParent, nil
+}
+
+// Returns credential of the user, by name or uid, with its primary
+// group. Nil if no user is given.
+func lookupCredential(name string) (*syscall.Credential, error) {
+	if name == "" {
+		return nil, nil
+	}
+	u, err := user.Lookup(name)
+	if _, ok := err.(user.UnknownUserError); ok {
+		u, err = user.LookupId(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed looking up user %s: %w", name, err)
+	}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid of user %s: %w", name, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gid of user %s: %w", name, err)
+	}
+
+	return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}, nil
+}
+
+// Returns the error as a gRPC status with given code, unless
+// it is a status already
+func toStatus(code codes.Code, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return status.Error(code, err.Error())
+}
+
+func errJobNotFound(jobID string) error {
+	return status.Errorf(codes.NotFound, "job id %s not found", jobID)
+}
+
 func (m *JobManager) getJobInfo(clientID string, jobID string) *JobInfo {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -162,77 +649,386 @@ func (m *JobManager) getJobInfo(clientID string, jobID string) *JobInfo {
 	return jobInfo
 }
 
-func getFilesystemMount(path string) (string, error) {
-	// Get absolute path
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path on %s: %w", path, err)
+// Returns limits to be applied to a job after replacing unset values
+// with defaults. Fails if any value exceeds its ceiling.
+func resolveLimits(requested *proto.ResourceLimits,
+	defaults, ceilings *LimitsConfig) (*proto.ResourceLimits, error) {
+	limits := &proto.ResourceLimits{
+		CpuQuotaMs:  defaults.CPUQuotaMs,
+		CpuPeriodMs: defaults.CPUPeriodMs,
+		MemoryKb:    defaults.MemoryKB,
+		ReadBps:     defaults.ReadBps,
+		WriteBps:    defaults.WriteBps,
+		ReadIops:    defaults.ReadIOPS,
+		WriteIops:   defaults.WriteIOPS,
+		MaxPids:     defaults.MaxPIDs,
 	}
-
-	// Read /proc/mounts
-	file, err := os.Open("/proc/mounts")
-	if err != nil {
-		return "", fmt.Errorf("failed to read /proc/mounts: %w", err)
+	if requested == nil {
+		requested = &proto.ResourceLimits{}
+	}
+	for _, v := range []struct {
+		name      string
+		requested int64
+		ceiling   int64
+		target    *int64
+	}{
+		{"cpu period", requested.CpuPeriodMs, ceilings.CPUPeriodMs, &limits.CpuPeriodMs},
+		{"memory", requested.MemoryKb, ceilings.MemoryKB, &limits.MemoryKb},
+		{"read bps", requested.ReadBps, ceilings.ReadBps, &limits.ReadBps},
+		{"write bps", requested.WriteBps, ceilings.WriteBps, &limits.WriteBps},
+		{"read iops", requested.ReadIops, ceilings.ReadIOPS, &limits.ReadIops},
+		{"write iops", requested.WriteIops, ceilings.WriteIOPS, &limits.WriteIops},
+		{"max pids", requested.MaxPids, ceilings.MaxPIDs, &limits.MaxPids},
+	} {
+		if v.requested < 0 || v.requested > v.ceiling {
+			return nil, fmt.Errorf("%s limit %d out of range [0, %d]",
+				v.name, v.requested, v.ceiling)
+		}
+		if v.requested != 0 {
+			*v.target = v.requested
+		}
+	}
+	// Bounded before the ratios are compared so the products cannot overflow
+	if requested.CpuQuotaMs < 0 || requested.CpuQuotaMs > cgroups.MaxCPUQuotaMs {
+		return nil, fmt.Errorf("cpu quota %d out of range [0, %d]",
+			requested.CpuQuotaMs, cgroups.MaxCPUQuotaMs)
+	}
+	if requested.CpuQuotaMs != 0 {
+		limits.CpuQuotaMs = requested.CpuQuotaMs
+	}
+	// The CPU ceiling is a share of the period, compare the ratios
+	if limits.CpuQuotaMs*ceilings.CPUPeriodMs > ceilings.CPUQuotaMs*limits.CpuPeriodMs {
+		return nil, fmt.Errorf("cpu limit %d/%d exceeds maximum %d/%d",
+			limits.CpuQuotaMs, limits.CpuPeriodMs,
+			ceilings.CPUQuotaMs, ceilings.CPUPeriodMs)
+	}
+	if err := resolveMemoryControls(requested, limits, ceilings); err != nil {
+		return nil, err
+	}
+	if err := resolveCPUPlacement(requested, limits); err != nil {
+		return nil, err
+	}
+	if err := resolveIODevices(requested, limits, ceilings); err != nil {
+		return nil, err
+	}
+	// Unrequested defaults must be within ceilings as well
+	if limits.MemoryKb > ceilings.MemoryKB || limits.ReadBps > ceilings.ReadBps ||
+		limits.WriteBps > ceilings.WriteBps || limits.CpuPeriodMs > ceilings.CPUPeriodMs ||
+		limits.ReadIops > ceilings.ReadIOPS || limits.WriteIops > ceilings.WriteIOPS ||
+		limits.MaxPids > ceilings.MaxPIDs {
+		return nil, fmt.Errorf("limits %v exceed maximum %s", limits, ceilings)
 	}
-	defer file.Close()
 
-	// Find the most specific mount point
-	var longestMatch string
-	maxLen := -1
+	return limits, nil
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		// Example format:
-		// sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
-		fields := strings.Fields(scanner.Text())
-		if len(fields) < 3 {
-			continue
+// Returns timeout to be applied to a job, the default one if not
+// requested. Zero means no timeout.
+func resolveTimeout(requested *durationpb.Duration, config *Config) (time.Duration, error) {
+	if requested == nil {
+		// The default never exceeds the ceiling, even if not validated
+		if config.MaxTimeout > 0 &&
+			(config.DefaultTimeout == 0 || config.DefaultTimeout > config.MaxTimeout) {
+			return config.MaxTimeout, nil
 		}
-		mountPoint := strings.TrimSpace(fields[1])
+		return config.DefaultTimeout, nil
+	}
+	if err := requested.CheckValid(); err != nil {
+		return 0, fmt.Errorf("invalid timeout: %w", err)
+	}
+	timeout := requested.AsDuration()
+	if timeout <= 0 || (config.MaxTimeout > 0 && timeout > config.MaxTimeout) {
+		return 0, fmt.Errorf("timeout %s out of range (0, %s]", timeout, config.MaxTimeout)
+	}
+
+	return timeout, nil
+}
+
+// Network mode and egress rules of a job
+type networkSpec struct {
+	mode   netns.Mode
+	egress []netns.EgressRule
+}
+
+var networkModes = map[proto.NetworkMode]netns.Mode{
+	proto.NetworkMode_NETWORK_MODE_NONE:     netns.ModeNone,
+	proto.NetworkMode_NETWORK_MODE_LOOPBACK: netns.ModeLoopback,
+	proto.NetworkMode_NETWORK_MODE_BRIDGED:  netns.ModeBridged,
+	proto.NetworkMode_NETWORK_MODE_NAT:      netns.ModeNAT,
+}
 
-		// Check if this mount point is a parent of our path
-		rel, err := filepath.Rel(mountPoint, absPath)
+// Returns network of a job as per the policy of its client. The mode
+// defaults to the one of the policy, and egress rules to the ones of
+// the policy if the mode is connected. Requested rules must be within
+// the ones of the policy, and are deduplicated.
+func resolveNetwork(req *proto.LaunchJobRequest,
+	policy *NetworkPolicyConfig) (networkSpec, error) {
+	mode := policy.DefaultMode
+	if req.NetworkMode != proto.NetworkMode_NETWORK_MODE_UNSPECIFIED {
+		var found bool
+		if mode, found = networkModes[req.NetworkMode]; !found {
+			return networkSpec{}, fmt.Errorf("invalid network mode %s", req.NetworkMode)
+		}
+	}
+	if !policy.isAllowed(mode) {
+		return networkSpec{}, status.Errorf(codes.PermissionDenied,
+			"network mode %s is not allowed", mode)
+	}
+	if !mode.IsConnected() {
+		if len(req.Egress) != 0 {
+			return networkSpec{}, fmt.Errorf("egress rules need bridged or nat mode")
+		}
+		return networkSpec{mode: mode}, nil
+	}
+	ceilings, err := policy.getEgress()
+	if err != nil {
+		return networkSpec{}, err
+	}
+	if len(req.Egress) == 0 {
+		return networkSpec{mode: mode, egress: ceilings}, nil
+	}
+	// Bounded before parsing since every rule ends up in the kernel
+	if len(req.Egress) > netns.MaxEgressRules {
+		return networkSpec{}, fmt.Errorf("%d egress rules, at most %d allowed",
+			len(req.Egress), netns.MaxEgressRules)
+	}
+	egress := []netns.EgressRule{}
+	for _, requested := range req.Egress {
+		if len(requested.Ports) > netns.MaxEgressPorts {
+			return networkSpec{}, fmt.Errorf("egress rule has %d ports, at most %d allowed",
+				len(requested.Ports), netns.MaxEgressPorts)
+		}
+		ports := []uint16{}
+		for _, port := range requested.Ports {
+			if port > math.MaxUint16 {
+				return networkSpec{}, fmt.Errorf("invalid egress port %d", port)
+			}
+			ports = append(ports, uint16(port))
+		}
+		rule, err := netns.NewEgressRule(requested.Cidr, ports)
 		if err != nil {
-			continue
+			return networkSpec{}, err
+		}
+		if len(ceilings) != 0 && !slices.ContainsFunc(ceilings, rule.IsWithin) {
+			return networkSpec{}, status.Errorf(codes.PermissionDenied,
+				"egress %s is not allowed", rule)
+		}
+		egress = append(egress, rule)
+	}
+	if egress, err = netns.CompactEgressRules(egress); err != nil {
+		return networkSpec{}, err
+	}
+
+	return networkSpec{mode: mode, egress: egress}, nil
+}
+
+func getNetworkModeProto(mode netns.Mode) proto.NetworkMode {
+	for protoMode, m := range networkModes {
+		if m == mode {
+			return protoMode
 		}
+	}
 
-		// Skip if path goes outside mount point
-		if strings.HasPrefix(rel, "..") {
-			continue
+	return proto.NetworkMode_NETWORK_MODE_UNSPECIFIED
+}
+
+// Returns the egress rules as in job entries
+func getEgressProto(egress []netns.EgressRule) []*proto.EgressRule {
+	rules := []*proto.EgressRule{}
+	for _, rule := range egress {
+		ports := []uint32{}
+		for _, port := range rule.Ports {
+			ports = append(ports, uint32(port))
 		}
+		rules = append(rules, &proto.EgressRule{Cidr: rule.Network.String(), Ports: ports})
+	}
 
-		// Keep track of the longest (most specific) mount point
-		if len(mountPoint) > maxLen {
-			maxLen = len(mountPoint)
-			longestMatch = mountPoint
+	return rules
+}
+
+// Creates parent control group of the jobs with the controllers
+// enabled and reports the active ones. The group is created on
+// given filesystem, or the real one if nil.
+func newCGroupParent(logger shared.Logger, config *Config,
+	fs cgroups.FS) (*cgroups.ParentGroup, error) {
+	if config.CGroupParent == "" {
+		return nil, nil
+	}
+	var options []cgroups.Option
+	if fs != nil {
+		options = append(options, cgroups.WithFS(fs))
+	}
+	parent, err := cgroups.NewParentGroup(config.CGroupParent,
+		cgroups.DefaultControllers, config.CGroupStrict, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating cgroup parent: %w", err)
+	}
+	logger.Infof("Jobs cgroup parent %s, active controllers %v",
+		parent.GetPath(), parent.GetControllers())
+	for _, controller := range cgroups.DefaultControllers {
+		if !slices.Contains(parent.GetControllers(), controller) {
+			logger.Warnf("%s controller could not be enabled, its limits are skipped",
+				controller)
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading /proc/mounts: %w", err)
+	return parent, nil
+}
+
+// Copies the requested CPU weight and pinning, which have no defaults.
+// Pinning is checked against CPUs available to the server at launch.
+func resolveCPUPlacement(requested, limits *proto.ResourceLimits) error {
+	if requested.CpuWeight != 0 && (requested.CpuWeight < cgroups.MinCPUWeight ||
+		requested.CpuWeight > cgroups.MaxCPUWeight) {
+		return fmt.Errorf("cpu weight %d out of range [%d, %d]", requested.CpuWeight,
+			cgroups.MinCPUWeight, cgroups.MaxCPUWeight)
+	}
+	if _, err := cgroups.ParseCPUList(requested.CpusetCpus); err != nil {
+		return fmt.Errorf("invalid cpuset cpus: %w", err)
 	}
+	if _, err := cgroups.ParseCPUList(requested.CpusetMems); err != nil {
+		return fmt.Errorf("invalid cpuset mems: %w", err)
+	}
+	limits.CpuWeight = requested.CpuWeight
+	limits.CpusetCpus = requested.CpusetCpus
+	limits.CpusetMems = requested.CpusetMems
+
+	return nil
+}
 
-	if maxLen == -1 {
-		return "", fmt.Errorf("no filesystem found for %s", path)
+// Copies the requested IO weight and per device limits, which are
+// bound by the same ceilings as the job limits
+func resolveIODevices(requested, limits *proto.ResourceLimits, ceilings *LimitsConfig) error {
+	if err := validateIOWeight(requested.IoWeight); err != nil {
+		return err
 	}
+	for _, device := range requested.IoDevices {
+		for _, v := range []struct {
+			name      string
+			requested int64
+			ceiling   int64
+		}{
+			{"read bps", device.ReadBps, ceilings.ReadBps},
+			{"write bps", device.WriteBps, ceilings.WriteBps},
+			{"read iops", device.ReadIops, ceilings.ReadIOPS},
+			{"write iops", device.WriteIops, ceilings.WriteIOPS},
+		} {
+			if v.requested < 0 || v.requested > v.ceiling {
+				return fmt.Errorf("%s limit %d of device %d:%d out of range [0, %d]",
+					v.name, v.requested, device.DeviceMajorNum, device.DeviceMinorNum,
+					v.ceiling)
+			}
+		}
+		if err := validateIOWeight(device.Weight); err != nil {
+			return err
+		}
+	}
+	limits.IoWeight = requested.IoWeight
+	limits.IoDevices = requested.IoDevices
 
-	return longestMatch, nil
+	return nil
 }
 
-func getDeviceNumbers(dirPath string) (int32, int32, error) {
-	info, err := os.Stat(dirPath)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to stat directory %s: %w", dirPath, err)
+func validateIOWeight(weight int64) error {
+	if weight != 0 && (weight < cgroups.MinIOWeight || weight > cgroups.MaxIOWeight) {
+		return fmt.Errorf("io weight %d out of range [%d, %d]", weight,
+			cgroups.MinIOWeight, cgroups.MaxIOWeight)
+	}
+
+	return nil
+}
+
+// Returns IO controls of a job, the job limits applied to every device
+// backing its root and the requested devices with their own limits.
+// Requested partitions are resolved to their disks.
+func getIOSpec(limits *proto.ResourceLimits, devices []mountfs.Device) (cgroups.IOSpec, error) {
+	spec := cgroups.IOSpec{Weight: limits.IoWeight}
+	for _, device := range devices {
+		spec.Devices = append(spec.Devices, cgroups.IODeviceLimit{
+			DeviceMajorNum: device.MajorNum, DeviceMinorNum: device.MinorNum,
+			ReadBps: limits.ReadBps, WriteBps: limits.WriteBps,
+			ReadIOPS: limits.ReadIops, WriteIOPS: limits.WriteIops})
 	}
+	for _, requested := range limits.IoDevices {
+		disk, err := mountfs.GetDisk(mountfs.Device{MajorNum: requested.DeviceMajorNum,
+			MinorNum: requested.DeviceMinorNum})
+		if err != nil {
+			return cgroups.IOSpec{}, err
+		}
+		device := cgroups.IODeviceLimit{DeviceMajorNum: disk.MajorNum,
+			DeviceMinorNum: disk.MinorNum, Weight: requested.Weight}
+		for _, v := range []struct {
+			requested int64
+			job       int64
+			target    *int64
+		}{
+			{requested.ReadBps, limits.ReadBps, &device.ReadBps},
+			{requested.WriteBps, limits.WriteBps, &device.WriteBps},
+			{requested.ReadIops, limits.ReadIops, &device.ReadIOPS},
+			{requested.WriteIops, limits.WriteIops, &device.WriteIOPS},
+		} {
+			*v.target = v.job
+			if v.requested != 0 {
+				*v.target = v.requested
+			}
+		}
+		i := slices.IndexFunc(spec.Devices, func(d cgroups.IODeviceLimit) bool {
+			return d.DeviceMajorNum == disk.MajorNum && d.DeviceMinorNum == disk.MinorNum
+		})
+		if i == -1 {
+			spec.Devices = append(spec.Devices, device)
+		} else {
+			spec.Devices[i] = device
+		}
+	}
+
+	return spec, nil
+}
 
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
-		return 0, 0, fmt.Errorf("failed to get system stat info for %s", dirPath)
+// Copies the requested memory controls other than the memory limit,
+// which must be resolved already. These have no defaults.
+func resolveMemoryControls(requested, limits *proto.ResourceLimits,
+	ceilings *LimitsConfig) error {
+	if requested.MemoryHighKb < 0 || requested.MemoryHighKb > limits.MemoryKb {
+		return fmt.Errorf("memory high %d out of range [0, %d]",
+			requested.MemoryHighKb, limits.MemoryKb)
+	}
+	maxLowKB := min(limits.MemoryKb, ceilings.MemoryLowKB)
+	if requested.MemoryLowKb < 0 || requested.MemoryLowKb > maxLowKB {
+		return fmt.Errorf("memory low %d out of range [0, %d]",
+			requested.MemoryLowKb, maxLowKB)
 	}
+	maxMinKB := min(limits.MemoryKb, ceilings.MemoryMinKB)
+	if requested.MemoryMinKb < 0 || (requested.MemoryLowKb != 0 &&
+		requested.MemoryMinKb > requested.MemoryLowKb) ||
+		requested.MemoryMinKb > maxMinKB {
+		return fmt.Errorf("memory min %d exceeds memory low %d or %d",
+			requested.MemoryMinKb, requested.MemoryLowKb, maxMinKB)
+	}
+	if requested.MemorySwapMaxKb != nil && (*requested.MemorySwapMaxKb < 0 ||
+		*requested.MemorySwapMaxKb > ceilings.MemorySwapKB) {
+		return fmt.Errorf("memory swap %d out of range [0, %d]",
+			*requested.MemorySwapMaxKb, ceilings.MemorySwapKB)
+	}
+	limits.MemoryHighKb = requested.MemoryHighKb
+	limits.MemoryLowKb = requested.MemoryLowKb
+	limits.MemoryMinKb = requested.MemoryMinKb
+	limits.MemorySwapMaxKb = requested.MemorySwapMaxKb
+	limits.MemoryOomGroup = requested.MemoryOomGroup
+
+	return nil
+}
 
-	// Get device numbers from the filesystem device ID
-	major := int32((stat.Dev >> 8) & 0xfff)
-	minor := int32(stat.Dev & 0xff)
+// Returns the disks backing roots of the jobs, on which the IO limits apply
+func getRootBaseDevices(logger shared.Logger, rootBase string) ([]mountfs.Device, error) {
+	devices, err := mountfs.GetBackingDevices(rootBase)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		logger.Warnf("No block device found backing %s, IO limits are skipped", rootBase)
+	}
+	logger.Infof("Using devices %v for IO limits of jobs under %s", devices, rootBase)
 
-	return major, minor, nil
+	return devices, nil
 }
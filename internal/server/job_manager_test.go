@@ -0,0 +1,840 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/troplet/internal/cgroupstest"
+	"github.com/troplet/internal/mountfstest"
+	"github.com/troplet/pkg/exec"
+	"github.com/troplet/pkg/exec/cgroups"
+	"github.com/troplet/pkg/exec/netns"
+	"github.com/troplet/pkg/proto"
+)
+
+// Scripted job writing its output and exiting with its code, or
+// running till stopped if it has no code
+type fakeJob struct {
+	id       string
+	spec     exec.CommandSpec
+	output   string
+	exitCode *int
+	ip       net.IP
+	lock     sync.Mutex
+	stopped  chan struct{}
+	done     bool
+	reason   exec.TerminationReason
+	outcome  exec.StopOutcome
+	// Returned by Stop instead of stopping if set
+	stopErr error
+}
+
+func (j *fakeJob) GetID() string { return j.id }
+
+func (j *fakeJob) GetIP() net.IP { return j.ip }
+
+func (j *fakeJob) Execute(ctx context.Context) error {
+	j.spec.StdoutChan <- []byte(j.output)
+	close(j.spec.StdoutChan)
+	close(j.spec.StderrChan)
+	reason := exec.TerminationReasonExited
+	if j.exitCode == nil {
+		select {
+		case <-j.stopped:
+		case <-ctx.Done():
+		}
+		reason = exec.TerminationReasonStopped
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.done, j.reason = true, reason
+
+	return nil
+}
+
+func (j *fakeJob) IsTerminated() bool {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.done
+}
+
+func (j *fakeJob) GetExitError() (error, error) {
+	if code := j.getExitCode(); code != 0 {
+		return fmt.Errorf("exit status %d", code), nil
+	}
+
+	return nil, nil
+}
+
+func (j *fakeJob) GetExitCode() (int, error) { return j.getExitCode(), nil }
+
+func (j *fakeJob) getExitCode() int {
+	if j.exitCode == nil {
+		return -1
+	}
+
+	return *j.exitCode
+}
+
+func (j *fakeJob) GetTerminationReason() exec.TerminationReason {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.reason
+}
+
+func (j *fakeJob) GetStopOutcome() exec.StopOutcome {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	return j.outcome
+}
+
+func (j *fakeJob) IsTimedOut() bool { return false }
+
+func (j *fakeJob) Signal(sig syscall.Signal) error { return nil }
+
+func (j *fakeJob) Stop(gracePeriod time.Duration) error {
+	if j.stopErr != nil {
+		return j.stopErr
+	}
+	j.lock.Lock()
+	j.outcome = exec.StopOutcomeGraceful
+	j.lock.Unlock()
+	close(j.stopped)
+
+	return nil
+}
+
+func (j *fakeJob) Pause() error { return nil }
+
+func (j *fakeJob) Resume() error { return nil }
+
+func (j *fakeJob) Resize(rows, cols uint16) error { return nil }
+
+func (j *fakeJob) Stats() (*cgroups.Stats, error) { return &cgroups.Stats{}, nil }
+
+func (j *fakeJob) Finish() error { return nil }
+
+// Runs scripted jobs by command name
+type fakeExecutor struct {
+	jobs map[string]*fakeJob
+}
+
+func (e *fakeExecutor) NewJob(name string, args []string,
+	options ...exec.CommandOption) (exec.Job, error) {
+	job, found := e.jobs[name]
+	if !found {
+		return nil, fmt.Errorf("unknown command %s", name)
+	}
+	job.spec = exec.NewCommandSpec(name, args, options...)
+
+	return job, nil
+}
+
+func TestResolveLimits(t *testing.T) {
+	defaults := &LimitsConfig{CPUQuotaMs: 100, CPUPeriodMs: 1000, MemoryKB: 1024,
+		ReadBps: 100, WriteBps: 100, ReadIOPS: 10, WriteIOPS: 10, MaxPIDs: 8}
+	ceilings := &LimitsConfig{CPUQuotaMs: 500, CPUPeriodMs: 1000, MemoryKB: 4096,
+		MemorySwapKB: 1024, ReadBps: 1000, WriteBps: 1000, ReadIOPS: 100,
+		WriteIOPS: 100, MaxPIDs: 64}
+	tests := []struct {
+		name      string
+		requested *proto.ResourceLimits
+		expect    *proto.ResourceLimits
+		expectErr bool
+	}{
+		{
+			name:      "Defaults",
+			requested: nil,
+			expect: &proto.ResourceLimits{CpuQuotaMs: 100, CpuPeriodMs: 1000,
+				MemoryKb: 1024, ReadBps: 100, WriteBps: 100, ReadIops: 10,
+				WriteIops: 10, MaxPids: 8},
+		},
+		{
+			name:      "Requested within ceilings",
+			requested: &proto.ResourceLimits{CpuQuotaMs: 50, CpuPeriodMs: 100, MaxPids: 64},
+			expect: &proto.ResourceLimits{CpuQuotaMs: 50, CpuPeriodMs: 100,
+				MemoryKb: 1024, ReadBps: 100, WriteBps: 100, ReadIops: 10,
+				WriteIops: 10, MaxPids: 64},
+		},
+		{
+			name:      "Memory beyond ceiling",
+			requested: &proto.ResourceLimits{MemoryKb: 8192},
+			expectErr: true,
+		},
+		{
+			name:      "CPU share beyond ceiling",
+			requested: &proto.ResourceLimits{CpuQuotaMs: 60, CpuPeriodMs: 100},
+			expectErr: true,
+		},
+		{
+			name:      "CPU quota overflowing the share",
+			requested: &proto.ResourceLimits{CpuQuotaMs: math.MaxInt64 / 2},
+			expectErr: true,
+		},
+		{
+			name: "CPU period overflowing the share",
+			requested: &proto.ResourceLimits{CpuQuotaMs: 1,
+				CpuPeriodMs: math.MaxInt64 / 2},
+			expectErr: true,
+		},
+		{
+			name:      "Negative pids",
+			requested: &proto.ResourceLimits{MaxPids: -1},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		limits, err := resolveLimits(test.requested, defaults, ceilings)
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expect, limits, protocmp.Transform()); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestResolveMemoryControls(t *testing.T) {
+	ceilings := &LimitsConfig{MemoryKB: 4096, MemorySwapKB: 1024, MemoryLowKB: 2048,
+		MemoryMinKB: 1024}
+	swap, overSwap := int64(512), int64(2048)
+	tests := []struct {
+		name      string
+		requested *proto.ResourceLimits
+		expect    *proto.ResourceLimits
+		expectErr bool
+	}{
+		{
+			name:      "None requested",
+			requested: &proto.ResourceLimits{},
+			expect:    &proto.ResourceLimits{MemoryKb: 3072},
+		},
+		{
+			name: "All within ceilings",
+			requested: &proto.ResourceLimits{MemoryHighKb: 2560, MemoryLowKb: 2048,
+				MemoryMinKb: 1024, MemorySwapMaxKb: &swap, MemoryOomGroup: true},
+			expect: &proto.ResourceLimits{MemoryKb: 3072, MemoryHighKb: 2560,
+				MemoryLowKb: 2048, MemoryMinKb: 1024, MemorySwapMaxKb: &swap,
+				MemoryOomGroup: true},
+		},
+		{
+			name:      "High beyond limit",
+			requested: &proto.ResourceLimits{MemoryHighKb: 4096},
+			expectErr: true,
+		},
+		{
+			name:      "Low beyond ceiling",
+			requested: &proto.ResourceLimits{MemoryLowKb: 2560},
+			expectErr: true,
+		},
+		{
+			name:      "Min beyond ceiling",
+			requested: &proto.ResourceLimits{MemoryMinKb: 1536},
+			expectErr: true,
+		},
+		{
+			name:      "Min beyond low",
+			requested: &proto.ResourceLimits{MemoryLowKb: 256, MemoryMinKb: 512},
+			expectErr: true,
+		},
+		{
+			name:      "Negative min",
+			requested: &proto.ResourceLimits{MemoryMinKb: -1},
+			expectErr: true,
+		},
+		{
+			name:      "Swap beyond ceiling",
+			requested: &proto.ResourceLimits{MemorySwapMaxKb: &overSwap},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		limits := &proto.ResourceLimits{MemoryKb: 3072}
+		err := resolveMemoryControls(test.requested, limits, ceilings)
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if err == nil {
+			if diff := cmp.Diff(test.expect, limits, protocmp.Transform()); diff != "" {
+				t.Errorf("Unexpected result: %s", diff)
+			}
+		}
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaultTimeout time.Duration
+		maxTimeout     time.Duration
+		requested      *durationpb.Duration
+		expect         time.Duration
+		expectErr      bool
+	}{
+		{"Default", time.Hour, 24 * time.Hour, nil, time.Hour, false},
+		{"No default without ceiling", 0, 0, nil, 0, false},
+		{"No default clamped to ceiling", 0, 24 * time.Hour, nil, 24 * time.Hour, false},
+		{"Requested within ceiling", time.Hour, 24 * time.Hour,
+			durationpb.New(2 * time.Hour), 2 * time.Hour, false},
+		{"Requested without ceiling", 0, 0,
+			durationpb.New(48 * time.Hour), 48 * time.Hour, false},
+		{"Requested beyond ceiling", time.Hour, 24 * time.Hour,
+			durationpb.New(25 * time.Hour), 0, true},
+		{"Requested zero", time.Hour, 24 * time.Hour, durationpb.New(0), 0, true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		config := &Config{DefaultTimeout: test.defaultTimeout, MaxTimeout: test.maxTimeout}
+		timeout, err := resolveTimeout(test.requested, config)
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expect, timeout); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestResolveNetwork(t *testing.T) {
+	policy := &NetworkPolicyConfig{DefaultMode: netns.ModeBridged,
+		Modes: []netns.Mode{netns.ModeNone, netns.ModeNAT},
+		Egress: []EgressRuleConfig{{CIDR: "10.0.0.0/8"},
+			{CIDR: "0.0.0.0/0", Ports: []uint16{443}}}}
+	manyRules, manyPorts := []*proto.EgressRule{}, []uint32{}
+	for i := 0; i <= netns.MaxEgressRules; i++ {
+		manyRules = append(manyRules, &proto.EgressRule{
+			Cidr: fmt.Sprintf("10.0.%d.0/24", i)})
+	}
+	for i := 0; i <= netns.MaxEgressPorts; i++ {
+		manyPorts = append(manyPorts, uint32(1000+i))
+	}
+	tests := []struct {
+		name         string
+		mode         proto.NetworkMode
+		egress       []*proto.EgressRule
+		expectMode   netns.Mode
+		expectEgress []string
+		expectErr    bool
+	}{
+		{"Defaults", proto.NetworkMode_NETWORK_MODE_UNSPECIFIED, nil,
+			netns.ModeBridged, []string{"10.0.0.0/8", "0.0.0.0/0:443"}, false},
+		{"Allowed mode", proto.NetworkMode_NETWORK_MODE_NONE, nil,
+			netns.ModeNone, nil, false},
+		{"Disallowed mode", proto.NetworkMode_NETWORK_MODE_LOOPBACK, nil,
+			"", nil, true},
+		{"Invalid mode", proto.NetworkMode(10), nil, "", nil, true},
+		{"Egress within policy", proto.NetworkMode_NETWORK_MODE_NAT,
+			[]*proto.EgressRule{{Cidr: "10.1.0.0/16", Ports: []uint32{80}},
+				{Cidr: "1.1.1.1/32", Ports: []uint32{443}}},
+			netns.ModeNAT, []string{"10.1.0.0/16:80", "1.1.1.1/32:443"}, false},
+		{"Egress beyond policy", proto.NetworkMode_NETWORK_MODE_NAT,
+			[]*proto.EgressRule{{Cidr: "1.1.1.1/32", Ports: []uint32{80}}},
+			"", nil, true},
+		{"Invalid port", proto.NetworkMode_NETWORK_MODE_NAT,
+			[]*proto.EgressRule{{Cidr: "10.0.0.0/8", Ports: []uint32{65536}}},
+			"", nil, true},
+		{"Egress without connected mode", proto.NetworkMode_NETWORK_MODE_NONE,
+			[]*proto.EgressRule{{Cidr: "10.0.0.0/8"}}, "", nil, true},
+		{"Duplicate egress", proto.NetworkMode_NETWORK_MODE_NAT,
+			[]*proto.EgressRule{{Cidr: "10.1.0.0/16", Ports: []uint32{443, 80, 80}},
+				{Cidr: "10.1.0.0/16", Ports: []uint32{80, 443}}},
+			netns.ModeNAT, []string{"10.1.0.0/16:80,443"}, false},
+		{"Too many egress rules", proto.NetworkMode_NETWORK_MODE_NAT, manyRules,
+			"", nil, true},
+		{"Too many egress ports", proto.NetworkMode_NETWORK_MODE_NAT,
+			[]*proto.EgressRule{{Cidr: "10.0.0.0/8", Ports: manyPorts}}, "", nil, true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		network, err := resolveNetwork(&proto.LaunchJobRequest{NetworkMode: test.mode,
+			Egress: test.egress}, policy)
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expectMode, network.mode); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		var egress []string
+		for _, rule := range network.egress {
+			egress = append(egress, rule.String())
+		}
+		if diff := cmp.Diff(test.expectEgress, egress); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+	// Anything goes without egress rules in the policy
+	policy.Egress = nil
+	if _, err := resolveNetwork(&proto.LaunchJobRequest{
+		Egress: []*proto.EgressRule{{Cidr: "1.1.1.1/32", Ports: []uint32{80}}}},
+		policy); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestJobManagerRestore(t *testing.T) {
+	dir := t.TempDir()
+	// Job still running when the server stopped
+	storePath := filepath.Join(dir, "jobs.journal")
+	store, err := NewJobStore(storePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entry := &proto.JobEntry{Id: "job-1", Command: "sleep", Args: []string{"10"},
+		StartTs: timestamppb.Now()}
+	if err := store.Save("client-1", entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	store.Close()
+
+	// Control groups and mounts are faked so that no privileges are needed
+	fs, err := cgroupstest.NewFakeFS(t.TempDir(), cgroups.DefaultControllers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config := DefaultConfig()
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	config.CGroupStrict = true
+	if store, err = NewJobStore(storePath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics(),
+		WithControlGroupFS(fs), WithMounter(mountfstest.NewFakeMounter()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Finish()
+	if diff := cmp.Diff(cgroups.DefaultControllers,
+		m.cgroupParent.GetControllers()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if !slices.Contains(fs.Writes(), cgroupstest.FakeWrite{
+		Path: "troplet.slice/cgroup.subtree_control", Value: "+memory"}) {
+		t.Errorf("Controllers not enabled for the jobs: %v", fs.Writes())
+	}
+	jobInfo := m.getJobInfo("client-1", "job-1")
+	if jobInfo == nil {
+		t.Fatalf("Job not restored")
+	}
+	restored := jobInfo.GetJobStatus()
+	if diff := cmp.Diff(proto.TerminationReason_TERMINATION_REASON_SERVER_SHUTDOWN,
+		restored.TerminationReason); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(serverRestartExitError, restored.GetExitError()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(true, protobuf.Equal(entry.StartTs, restored.StartTs)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestJobManagerLaunch(t *testing.T) {
+	exitCode := 3
+	executor := &fakeExecutor{jobs: map[string]*fakeJob{
+		"exits": {id: "job-1", output: "hello\n", exitCode: &exitCode},
+		"runs": {id: "job-2", output: "running\n", stopped: make(chan struct{}),
+			ip: net.ParseIP("10.88.0.2")},
+	}}
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	config.CGroupParent = ""
+	store, err := NewJobStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics(),
+		WithExecutor(executor))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Finish()
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		command      string
+		terminate    bool
+		expectOutput string
+		expectReason proto.TerminationReason
+		expectCode   int32
+		expectIP     string
+	}{
+		{"Exits with code", "exits", false, "hello\n",
+			proto.TerminationReason_TERMINATION_REASON_EXITED, 3, ""},
+		{"Terminated by user", "runs", true, "running\n",
+			proto.TerminationReason_TERMINATION_REASON_TERMINATED_BY_USER, -1, "10.88.0.2"},
+		{"Fails to start", "missing", false, "",
+			proto.TerminationReason_TERMINATION_REASON_FAILED_TO_START, 1, ""},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		jobID, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: test.command})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if test.terminate {
+			if err := m.Terminate(ctx, "client-1", jobID, nil); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+		m.getJobInfo("client-1", jobID).Wait()
+		entry, err := m.GetJobStatus(ctx, "client-1", jobID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expectReason, entry.TerminationReason); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		if diff := cmp.Diff(test.expectCode, entry.GetExitCode()); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		if diff := cmp.Diff(test.expectIP, entry.IpAddress); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		// Default policy of the client applies
+		if diff := cmp.Diff(proto.NetworkMode_NETWORK_MODE_LOOPBACK,
+			entry.NetworkMode); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		output := ""
+		if err := m.Attach(ctx, "client-1", jobID, 0, 0,
+			func(entry *proto.JobStreamEntry) error {
+				output += string(entry.Entry)
+				return nil
+			}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expectOutput, output); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestJobManagerProcessExecutor(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.Executor = ProcessExecutor
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	store, err := NewJobStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Jobs must not run as root
+	if os.Geteuid() == 0 {
+		if _, err := NewJobManager(zap.NewNop().Sugar(), config, store,
+			NewMetrics()); err == nil {
+			t.Errorf("Expected error for process executor without user")
+		}
+		config.ProcessUser = "65534"
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Finish()
+	ctx := context.Background()
+	jobID, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: "true",
+		Limits: &proto.ResourceLimits{MemoryKb: 16 * 1024}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.getJobInfo("client-1", jobID).Wait()
+	entry, err := m.GetJobStatus(ctx, "client-1", jobID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(proto.TerminationReason_TERMINATION_REASON_EXITED,
+		entry.TerminationReason); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Limits and network are not applied to plain processes, thus
+	// not reported
+	if entry.Limits != nil {
+		t.Errorf("Unexpected limits: %v", entry.Limits)
+	}
+	if diff := cmp.Diff(proto.NetworkMode_NETWORK_MODE_UNSPECIFIED,
+		entry.NetworkMode); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if len(entry.Egress) != 0 {
+		t.Errorf("Unexpected egress: %v", entry.Egress)
+	}
+	for _, req := range []*proto.LaunchJobRequest{
+		{Command: "true", NetworkMode: proto.NetworkMode_NETWORK_MODE_BRIDGED},
+		{Command: "true", Egress: []*proto.EgressRule{{Cidr: "10.0.0.0/8"}}},
+	} {
+		_, err := m.Launch(ctx, "client-1", req)
+		if diff := cmp.Diff(codes.FailedPrecondition, status.Code(err)); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestJobManagerErrorCodes(t *testing.T) {
+	executor := &fakeExecutor{jobs: map[string]*fakeJob{
+		"runs": {id: "job-1", stopped: make(chan struct{})},
+		"stuck": {id: "job-3", stopped: make(chan struct{}),
+			stopErr: fmt.Errorf("operation not permitted")},
+	}}
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	config.MaxRunningJobsPerClient = 1
+	store, err := NewJobStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics(),
+		WithExecutor(executor))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Finish()
+	ctx := context.Background()
+	if _, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: "runs"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.Launch(ctx, "client-3", &proto.LaunchJobRequest{Command: "stuck"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		call       func() error
+		expectCode codes.Code
+	}{
+		{"Limits beyond ceilings", func() error {
+			_, err := m.Launch(ctx, "client-2", &proto.LaunchJobRequest{Command: "runs",
+				Limits: &proto.ResourceLimits{MemoryKb: math.MaxInt32}})
+			return err
+		}, codes.InvalidArgument},
+		{"Disallowed network mode", func() error {
+			_, err := m.Launch(ctx, "client-2", &proto.LaunchJobRequest{Command: "runs",
+				NetworkMode: proto.NetworkMode_NETWORK_MODE_NAT})
+			return err
+		}, codes.PermissionDenied},
+		{"Running jobs quota", func() error {
+			_, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: "runs"})
+			return err
+		}, codes.ResourceExhausted},
+		{"Unknown job", func() error {
+			_, err := m.GetJobStatus(ctx, "client-1", "job-2")
+			return err
+		}, codes.NotFound},
+		{"Unknown signal", func() error {
+			return m.Signal(ctx, "client-1", "job-1", "FOO")
+		}, codes.InvalidArgument},
+		{"Failing to stop", func() error {
+			return m.Terminate(ctx, "client-3", "job-3", nil)
+		}, codes.Internal},
+		// Not reported as terminated after the failure
+		{"Failing to stop again", func() error {
+			return m.Terminate(ctx, "client-3", "job-3", nil)
+		}, codes.Internal},
+		{"Stop signal", func() error {
+			return m.Signal(ctx, "client-1", "job-1", "STOP")
+		}, codes.InvalidArgument},
+		{"Continue signal", func() error {
+			return m.Signal(ctx, "client-1", "job-1", "SIGCONT")
+		}, codes.InvalidArgument},
+		{"Stats interval below minimum", func() error {
+			return m.WatchStats(ctx, "client-1", "job-1",
+				durationpb.New(time.Millisecond), nil)
+		}, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		if diff := cmp.Diff(test.expectCode, status.Code(test.call())); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestJobManagerConcurrentLaunchQuota(t *testing.T) {
+	executor := &fakeExecutor{jobs: map[string]*fakeJob{}}
+	for i := 0; i < 8; i++ {
+		executor.jobs[fmt.Sprintf("runs-%d", i)] = &fakeJob{id: fmt.Sprintf("job-%d", i),
+			stopped: make(chan struct{})}
+	}
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	config.MaxRunningJobsPerClient = 2
+	store, err := NewJobStore("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics(),
+		WithExecutor(executor))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer m.Finish()
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	codeChan := make(chan codes.Code, len(executor.jobs))
+	for name := range executor.jobs {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: name})
+			codeChan <- status.Code(err)
+		}(name)
+	}
+	wg.Wait()
+	close(codeChan)
+	launched := 0
+	for code := range codeChan {
+		if code == codes.OK {
+			launched++
+		} else if code != codes.ResourceExhausted {
+			t.Errorf("Unexpected result: %s", code)
+		}
+	}
+	if diff := cmp.Diff(2, launched); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestJobManagerPurge(t *testing.T) {
+	exitCode := 0
+	executor := &fakeExecutor{jobs: map[string]*fakeJob{
+		"first":  {id: "job-1", output: "first\n", exitCode: &exitCode},
+		"second": {id: "job-2", output: "second\n", exitCode: &exitCode},
+		"runs":   {id: "job-3", stopped: make(chan struct{})},
+	}}
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.RootBase = filepath.Join(dir, "roots")
+	config.OutputDir = filepath.Join(dir, "output")
+	config.MaxTerminatedJobsPerClient = 1
+	storePath := filepath.Join(dir, "jobs.journal")
+	store, err := NewJobStore(storePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m, err := NewJobManager(zap.NewNop().Sugar(), config, store, NewMetrics(),
+		WithExecutor(executor))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	// Launching the last one purges the oldest terminated job
+	for _, command := range []string{"first", "second", "runs"} {
+		jobID, err := m.Launch(ctx, "client-1", &proto.LaunchJobRequest{Command: command})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if command != "runs" {
+			m.getJobInfo("client-1", jobID).Wait()
+		}
+	}
+	m.Finish()
+	if m.getJobInfo("client-1", "job-1") != nil {
+		t.Errorf("Job job-1 not purged")
+	}
+	if _, err := os.Stat(filepath.Join(config.OutputDir, "job-1")); !os.IsNotExist(err) {
+		t.Errorf("Output of job job-1 not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(config.OutputDir, "job-2")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if store, err = NewJobStore(storePath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+	clientEntries, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	jobIDs := []string{}
+	for _, entry := range clientEntries["client-1"] {
+		jobIDs = append(jobIDs, entry.Id)
+	}
+	if diff := cmp.Diff([]string{"job-2", "job-3"}, jobIDs); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestJobInfoDetachFullControlChannel(t *testing.T) {
+	// Room for a single control message, nothing drains it
+	jobInfo := NewJobInfo(zap.NewNop().Sugar(), NewMetrics(), 1, "sleep", nil, nil)
+	// Detaches share the read lock, the one finding the channel full
+	// must fail rather than block the output fan out taking the lock
+	done := make(chan error, 2)
+	for id := uint64(1); id <= 2; id++ {
+		go func() {
+			done <- jobInfo.detach(newSubscriber(id, 1, BackpressurePolicy))
+		}()
+	}
+	codesSeen := []codes.Code{}
+	for range 2 {
+		select {
+		case err := <-done:
+			codesSeen = append(codesSeen, status.Code(err))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Detach blocked on full control channel")
+		}
+	}
+	slices.Sort(codesSeen)
+	if diff := cmp.Diff([]codes.Code{codes.OK, codes.ResourceExhausted},
+		codesSeen); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestConfigKeepStartupSettings(t *testing.T) {
+	startup := &Config{Address: "0.0.0.0:16000", Executor: IsolatedExecutor,
+		RootBase: "/home/jobs", Network: NetworkConfig{Bridge: "troplet0",
+			Subnet: "10.88.0.0/16", LeaseDir: "/var/lib/leases"},
+		CGroupParent: "troplet.slice", MaxRunningJobsPerClient: 4}
+	reloaded := &Config{Address: "0.0.0.0:17000", Executor: ProcessExecutor,
+		RootBase: "/home/roots", Network: NetworkConfig{Bridge: "troplet1",
+			Subnet: "10.89.0.0/16", LeaseDir: "/var/lib/leases",
+			DefaultPolicy: NetworkPolicyConfig{DefaultMode: "nat"}},
+		CGroupParent: "troplet.slice", MaxRunningJobsPerClient: 8}
+	changed := reloaded.keepStartupSettings(startup)
+	if diff := cmp.Diff([]string{"address", "executor", "network.bridge",
+		"network.subnet"}, changed); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Only the reloadable settings change
+	expect := &Config{Address: "0.0.0.0:16000", Executor: IsolatedExecutor,
+		RootBase: "/home/roots", Network: NetworkConfig{Bridge: "troplet0",
+			Subnet: "10.88.0.0/16", LeaseDir: "/var/lib/leases",
+			DefaultPolicy: NetworkPolicyConfig{DefaultMode: "nat"}},
+		CGroupParent: "troplet.slice", MaxRunningJobsPerClient: 8}
+	if diff := cmp.Diff(expect, reloaded); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
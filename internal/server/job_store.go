@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/troplet/pkg/proto"
+)
+
+// JobStore persists job entries so that the job history
+// survives server restarts
+type JobStore interface {
+	// Records the latest state of the job launched by the client
+	Save(clientID string, entry *proto.JobEntry) error
+	// Forgets the job, such as once purged
+	Delete(clientID string, jobID string) error
+	// Returns the latest state of all the recorded jobs
+	// mapped by client id
+	Load() (map[string][]*proto.JobEntry, error)
+	Close() error
+}
+
+// Returns store as per configuration. Job history is kept only
+// in memory if the store path is not configured.
+func NewJobStore(path string) (JobStore, error) {
+	if path == "" {
+		return &memoryJobStore{}, nil
+	}
+
+	return NewJournalJobStore(path)
+}
+
+// Store that does not persist anything
+type memoryJobStore struct{}
+
+func (s *memoryJobStore) Save(clientID string, entry *proto.JobEntry) error {
+	return nil
+}
+
+func (s *memoryJobStore) Delete(clientID string, jobID string) error {
+	return nil
+}
+
+func (s *memoryJobStore) Load() (map[string][]*proto.JobEntry, error) {
+	return map[string][]*proto.JobEntry{}, nil
+}
+
+func (s *memoryJobStore) Close() error {
+	return nil
+}
+
+// Journal record, one JSON object per line
+type journalRecord struct {
+	ClientID string          `json:"client_id"`
+	Job      json.RawMessage `json:"job"`
+	// Set if the job got deleted, its record holds only the id
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// JournalJobStore appends every job transition to a file.
+// Later records of a job override earlier ones, and deleted
+// jobs are dropped on compaction.
+type JournalJobStore struct {
+	path string
+	// To serialize appends
+	lock sync.Mutex
+	file *os.File
+}
+
+func NewJournalJobStore(path string) (*JournalJobStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	return &JournalJobStore{path: path}, nil
+}
+
+func (s *JournalJobStore) Save(clientID string, entry *proto.JobEntry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("job store %s is not loaded", s.path)
+	}
+
+	return s.append(s.file, clientID, entry)
+}
+
+func (s *JournalJobStore) Delete(clientID string, jobID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("job store %s is not loaded", s.path)
+	}
+
+	return s.appendRecord(s.file, &journalRecord{ClientID: clientID, Deleted: true},
+		&proto.JobEntry{Id: jobID})
+}
+
+// Reads the journal and compacts it so that it holds only
+// the latest record of every job. Must be called before Save.
+func (s *JournalJobStore) Load() (map[string][]*proto.JobEntry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	clientIDs := map[string]string{}
+	entries := map[string]*proto.JobEntry{}
+	// Job ids in the order they were first seen
+	jobIDs := []string{}
+	file, err := os.Open(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to open %s: %w", s.path, err)
+	}
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			var record journalRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				// A torn write at the end of the journal
+				// is expected if the server died
+				continue
+			}
+			entry := &proto.JobEntry{}
+			if err := protojson.Unmarshal(record.Job, entry); err != nil {
+				continue
+			}
+			if record.Deleted {
+				delete(entries, entry.Id)
+				continue
+			}
+			if _, found := entries[entry.Id]; !found {
+				jobIDs = append(jobIDs, entry.Id)
+			}
+			entries[entry.Id] = entry
+			clientIDs[entry.Id] = record.ClientID
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed reading %s: %w", s.path, err)
+		}
+	}
+
+	// Rewrite compacted journal and swap it in
+	tmpPath := s.path + ".tmp"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", tmpPath, err)
+	}
+	ret := map[string][]*proto.JobEntry{}
+	for _, jobID := range jobIDs {
+		entry, found := entries[jobID]
+		if !found {
+			// Deleted
+			continue
+		}
+		clientID := clientIDs[jobID]
+		if err := s.append(tmpFile, clientID, entry); err != nil {
+			tmpFile.Close()
+			return nil, err
+		}
+		ret[clientID] = append(ret[clientID], entry)
+		// Written once even if deleted and then recorded again
+		delete(entries, jobID)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("failed to sync %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		tmpFile.Close()
+		return nil, fmt.Errorf("failed to rename %s: %w", tmpPath, err)
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	// Appends continue on the compacted journal
+	s.file = tmpFile
+
+	return ret, nil
+}
+
+func (s *JournalJobStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+func (s *JournalJobStore) append(file *os.File, clientID string, entry *proto.JobEntry) error {
+	return s.appendRecord(file, &journalRecord{ClientID: clientID}, entry)
+}
+
+func (s *JournalJobStore) appendRecord(file *os.File, record *journalRecord,
+	entry *proto.JobEntry) error {
+	job, err := protojson.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", entry.Id, err)
+	}
+	record.Job = job
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", entry.Id, err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed writing to %s: %w", s.path, err)
+	}
+
+	return nil
+}
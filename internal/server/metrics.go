@@ -0,0 +1,62 @@
+package server
+
+import (
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/troplet/pkg/exec/cgroups"
+)
+
+// Server wide counters, published through expvar
+type Metrics struct {
+	// Live output bytes not delivered to slow subscribers
+	DroppedBytes atomic.Int64
+	// Subscribers disconnected for being slow
+	DisconnectedSubscribers atomic.Int64
+	// Pressure stall events raised on jobs, per resource
+	CPUPressureStalls    atomic.Int64
+	MemoryPressureStalls atomic.Int64
+	IOPressureStalls     atomic.Int64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Publishes the metrics under given expvar name
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return map[string]int64{
+			"dropped_bytes":            m.DroppedBytes.Load(),
+			"disconnected_subscribers": m.DisconnectedSubscribers.Load(),
+			"cpu_pressure_stalls":      m.CPUPressureStalls.Load(),
+			"memory_pressure_stalls":   m.MemoryPressureStalls.Load(),
+			"io_pressure_stalls":       m.IOPressureStalls.Load(),
+		}
+	}))
+}
+
+func (m *Metrics) AddPressureStall(resource cgroups.PressureResource) {
+	switch resource {
+	case cgroups.PressureCPU:
+		m.CPUPressureStalls.Add(1)
+	case cgroups.PressureMemory:
+		m.MemoryPressureStalls.Add(1)
+	case cgroups.PressureIO:
+		m.IOPressureStalls.Add(1)
+	}
+}
+
+// Serves expvar metrics as JSON over HTTP
+func ServeMetrics(address string) error {
+	listen, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("metrics failed to listen on %s: %w", address, err)
+	}
+	go http.Serve(listen, expvar.Handler())
+
+	return nil
+}
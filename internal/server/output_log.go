@@ -0,0 +1,278 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/troplet/pkg/proto"
+)
+
+const (
+	outputLogSegmentSuffix = ".log"
+	// Record header holds stream flag, sequence, stream offset,
+	// capture timestamp in nanoseconds and data length
+	outputLogHeaderSize = 1 + 8 + 8 + 8 + 4
+)
+
+// OutputLog persists stdout and stderr chunks of a job in the order
+// these were read. Every chunk is assigned a sequence number starting
+// from one, and its byte offset within its own stream. Chunks are
+// stored in segment files named after the output offset, counted
+// across both streams, of their first byte. The oldest segments are
+// removed once the log exceeds its size cap.
+type OutputLog struct {
+	dir          string
+	segmentBytes int64
+	maxSegments  int
+	// To protect the segments, writer and end offset
+	lock sync.RWMutex
+	// Start offsets of available segments in ascending order
+	segments []int64
+	file     *os.File
+	fileSize int64
+	// Output offset after the last written byte
+	end int64
+	// Sequence of the last written chunk
+	sequence uint64
+	// Stream offsets after the last written byte
+	stdoutOffset int64
+	stderrOffset int64
+}
+
+// Returns output log kept under the given directory. Existing
+// segments are loaded so that the log of a terminated job
+// can be read after server restart.
+func NewOutputLog(dir string, maxBytes int64, maxSegments int) (*OutputLog, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	l := &OutputLog{dir: dir, maxSegments: maxSegments,
+		segmentBytes: maxBytes / int64(maxSegments)}
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	for _, dirEntry := range dirEntries {
+		name, found := strings.CutSuffix(dirEntry.Name(), outputLogSegmentSuffix)
+		if !found {
+			continue
+		}
+		start, err := strconv.ParseInt(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		l.segments = append(l.segments, start)
+	}
+	sort.Slice(l.segments, func(i, j int) bool { return l.segments[i] < l.segments[j] })
+	if len(l.segments) != 0 {
+		// Find end by walking the records of last segment
+		start := l.segments[len(l.segments)-1]
+		l.end = start
+		if err := l.readSegment(start, start, 0, math.MaxUint64,
+			func(entry *proto.JobStreamEntry) error {
+				l.end += int64(len(entry.Entry))
+				l.sequence = entry.Sequence
+				if entry.IsStdError {
+					l.stderrOffset = entry.Offset + int64(len(entry.Entry))
+				} else {
+					l.stdoutOffset = entry.Offset + int64(len(entry.Entry))
+				}
+				return nil
+			}); err != nil {
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// Appends a chunk of output captured at given time. Returns the
+// stream entry with its sequence and stream offset assigned. The
+// entry is returned even if persisting it fails.
+func (l *OutputLog) Write(isStdError bool, data []byte,
+	ts time.Time) (*proto.JobStreamEntry, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.sequence++
+	entry := &proto.JobStreamEntry{Entry: data, IsStdError: isStdError,
+		Sequence: l.sequence, Offset: l.stdoutOffset, Ts: timestamppb.New(ts)}
+	if isStdError {
+		entry.Offset = l.stderrOffset
+		l.stderrOffset += int64(len(data))
+	} else {
+		l.stdoutOffset += int64(len(data))
+	}
+	l.end += int64(len(data))
+	if l.file == nil || l.fileSize >= l.segmentBytes {
+		if err := l.rotate(); err != nil {
+			return entry, err
+		}
+	}
+	record := make([]byte, outputLogHeaderSize+len(data))
+	if isStdError {
+		record[0] = 1
+	}
+	binary.BigEndian.PutUint64(record[1:], entry.Sequence)
+	binary.BigEndian.PutUint64(record[9:], uint64(entry.Offset))
+	binary.BigEndian.PutUint64(record[17:], uint64(ts.UnixNano()))
+	binary.BigEndian.PutUint32(record[25:], uint32(len(data)))
+	copy(record[outputLogHeaderSize:], data)
+	n, err := l.file.Write(record)
+	l.fileSize += int64(n)
+	if err != nil {
+		return entry, fmt.Errorf("failed writing to %s: %w", l.file.Name(), err)
+	}
+
+	return entry, nil
+}
+
+// Returns output offset after the last written byte
+func (l *OutputLog) GetEnd() int64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.end
+}
+
+// Returns sequence of the last written chunk
+func (l *OutputLog) GetSequence() uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.sequence
+}
+
+// Reads chunks with sequence in range [fromSequence, toSequence]
+// starting at output offset from, and calls fn for every chunk.
+// Output that got rotated out is skipped.
+func (l *OutputLog) Read(from int64, fromSequence, toSequence uint64,
+	fn func(*proto.JobStreamEntry) error) error {
+	l.lock.RLock()
+	segments := append([]int64{}, l.segments...)
+	l.lock.RUnlock()
+	for i, start := range segments {
+		// Skip segments that end before the requested offset
+		if i+1 < len(segments) && segments[i+1] <= from {
+			continue
+		}
+		err := l.readSegment(start, from, fromSequence, toSequence, fn)
+		if errors.Is(err, os.ErrNotExist) {
+			// Got removed by rotation meanwhile
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Closes the writer. The log can still be read.
+func (l *OutputLog) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+
+	return err
+}
+
+func (l *OutputLog) rotate() error {
+	if l.file != nil {
+		if err := l.file.Close(); err != nil {
+			return fmt.Errorf("failed to close %s: %w", l.file.Name(), err)
+		}
+		l.file = nil
+	}
+	path := l.getSegmentPath(l.end)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	l.file = file
+	l.fileSize = 0
+	if len(l.segments) == 0 || l.segments[len(l.segments)-1] != l.end {
+		l.segments = append(l.segments, l.end)
+	}
+	for len(l.segments) > l.maxSegments {
+		if err := os.Remove(l.getSegmentPath(l.segments[0])); err != nil &&
+			!errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove segment: %w", err)
+		}
+		l.segments = l.segments[1:]
+	}
+
+	return nil
+}
+
+// Reads records of the segment starting at output offset from with
+// sequence in range [fromSequence, toSequence]
+func (l *OutputLog) readSegment(start, from int64, fromSequence, toSequence uint64,
+	fn func(*proto.JobStreamEntry) error) error {
+	file, err := os.Open(l.getSegmentPath(start))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	header := make([]byte, outputLogHeaderSize)
+	offset := start
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			// A partially written record is ignored
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return fmt.Errorf("failed reading %s: %w", file.Name(), err)
+		}
+		entry := &proto.JobStreamEntry{IsStdError: header[0] == 1,
+			Sequence: binary.BigEndian.Uint64(header[1:]),
+			Offset:   int64(binary.BigEndian.Uint64(header[9:])),
+			Ts:       timestamppb.New(time.Unix(0, int64(binary.BigEndian.Uint64(header[17:]))))}
+		if entry.Sequence > toSequence {
+			return nil
+		}
+		data := make([]byte, binary.BigEndian.Uint32(header[25:]))
+		if _, err := io.ReadFull(reader, data); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return fmt.Errorf("failed reading %s: %w", file.Name(), err)
+		}
+		recordStart := offset
+		offset += int64(len(data))
+		if offset <= from || entry.Sequence < fromSequence {
+			continue
+		}
+		// Trim the part before the requested offset
+		if recordStart < from {
+			entry.Offset += from - recordStart
+			data = data[from-recordStart:]
+		}
+		entry.Entry = data
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *OutputLog) getSegmentPath(start int64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%020d%s", start, outputLogSegmentSuffix))
+}
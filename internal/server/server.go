@@ -6,43 +6,38 @@ import (
 	"fmt"
 	"net"
 	"sort"
-	"sync"
+	"strings"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"github.com/troplet/internal/shared"
 	"github.com/troplet/pkg/proto"
 )
 
-type Config struct {
-	Address      string
-	CABundlePath string
-	CertPath     string
-	CertKeyPath  string
-}
-
 const cnCtxKey = "CommonName"
 
-func (c Config) String() string {
-	// Must not log sensitive credentials
-	return "\nAddress   :" + c.Address +
-		"\nCA bundle :" + c.CABundlePath +
-		"\nCert      :" + c.CertPath +
-		"\nCert key  :" + c.CertKeyPath
-}
-
 type Server struct {
-	config     *Config
+	// Configuration replaced on reload, keeping the startup values of
+	// the settings that are not reloaded
+	config     atomic.Pointer[Config]
 	logger     shared.Logger
 	jobManager *JobManager
 	grpcServer *grpc.Server
+	// TLS configuration used for new connections, replaced on reload
+	tlsConfig atomic.Pointer[tls.Config]
 	proto.UnimplementedJobServiceServer
 }
 
 func NewServer(config *Config, logger shared.Logger, jobManager *JobManager) *Server {
-	return &Server{config: config, logger: logger, jobManager: jobManager}
+	s := &Server{logger: logger, jobManager: jobManager}
+	s.config.Store(config)
+
+	return s
 }
 
 func (s *Server) Start() error {
@@ -53,10 +48,10 @@ func (s *Server) Start() error {
 	s.grpcServer = grpc.NewServer(grpc.Creds(tlsCredentials),
 		grpc.UnaryInterceptor(s.setPeerCertCNInCtx))
 	proto.RegisterJobServiceServer(s.grpcServer, s)
-	listen, err := net.Listen("tcp", s.config.Address)
+	address := s.config.Load().Address
+	listen, err := net.Listen("tcp", address)
 	if err != nil {
-		return fmt.Errorf("server failed to listen on %s: %w",
-			s.config.Address, err)
+		return fmt.Errorf("server failed to listen on %s: %w", address, err)
 	}
 	if err := s.grpcServer.Serve(listen); err != nil {
 		return fmt.Errorf("server failed to serve: %w", err)
@@ -65,6 +60,28 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// Applies reloaded configuration. Settings that are not reloaded,
+// such as the listener address and the network, keep their startup
+// values, and running jobs and connections are not affected.
+func (s *Server) Reload(config *Config) error {
+	reloaded := *config
+	if changed := reloaded.keepStartupSettings(s.config.Load()); len(changed) != 0 {
+		s.logger.Warnf("Changing %s requires restart, ignoring",
+			strings.Join(changed, ", "))
+	}
+	tlsConfig, err := s.createTLSConfig(&reloaded)
+	if err != nil {
+		return err
+	}
+	if err := s.jobManager.Reload(&reloaded); err != nil {
+		return err
+	}
+	s.tlsConfig.Store(tlsConfig)
+	s.config.Store(&reloaded)
+
+	return nil
+}
+
 func (s *Server) Finish() {
 	if s.grpcServer != nil {
 		s.grpcServer.GracefulStop()
@@ -93,72 +110,143 @@ func (s *Server) GetJobStatus(ctx context.Context,
 	return &proto.GetJobStatusResponse{Job: job}, nil
 }
 
+func (s *Server) GetJobStats(ctx context.Context,
+	req *proto.GetJobStatsRequest) (*proto.GetJobStatsResponse, error) {
+	stats, err := s.jobManager.GetStats(ctx, s.getCNFromCtx(ctx), req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.GetJobStatsResponse{Stats: stats}, nil
+}
+
+func (s *Server) WatchJobStats(req *proto.WatchJobStatsRequest,
+	stream proto.JobService_WatchJobStatsServer) error {
+	ctx := stream.Context()
+	commonName, err := s.getCNFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.jobManager.WatchStats(ctx, commonName, req.Id, req.Interval,
+		func(stats *proto.JobStats) error {
+			return stream.Send(&proto.WatchJobStatsResponse{Stats: stats})
+		})
+}
+
 func (s *Server) LaunchJob(ctx context.Context,
 	req *proto.LaunchJobRequest) (*proto.LaunchJobResponse, error) {
-	id := s.jobManager.Launch(ctx, s.getCNFromCtx(ctx), req.Command, req.Args)
+	id, err := s.jobManager.Launch(ctx, s.getCNFromCtx(ctx), req)
+	if err != nil {
+		return nil, err
+	}
 
 	return &proto.LaunchJobResponse{Id: id}, nil
 }
 
 func (s *Server) AttachJob(req *proto.AttachJobRequest, stream proto.JobService_AttachJobServer) error {
-	stdoutChan := make(chan *proto.JobStreamEntry)
-	stderrChan := make(chan *proto.JobStreamEntry)
 	ctx := stream.Context()
 	commonName, err := s.getCNFromContext(ctx)
 	if err != nil {
 		return err
 	}
-	subscriberID, err := s.jobManager.Attach(ctx, commonName, req.Id, stdoutChan, stderrChan)
+
+	// This blocks till the job output has been sent
+	// or the client terminated the connection
+	return s.jobManager.Attach(ctx, commonName, req.Id, req.Offset, req.FromSequence,
+		func(entry *proto.JobStreamEntry) error {
+			return stream.Send(&proto.AttachJobResponse{StreamEntry: entry})
+		})
+}
+
+func (s *Server) InteractJob(stream proto.JobService_InteractJobServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	commonName, err := s.getCNFromContext(ctx)
 	if err != nil {
 		return err
 	}
-	var wg sync.WaitGroup
-	wg.Add(1)
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	attach := req.GetAttach()
+	if attach == nil {
+		return status.Errorf(codes.InvalidArgument, "first request must attach to a job")
+	}
+
+	// Forward stdin till the client stops sending. Failing to
+	// forward ends the stream with that error.
+	stdinErrChan := make(chan error, 1)
 	go func() {
-		defer wg.Done()
-		numChannels := 2
-		for numChannels > 0 {
-			select {
-			case entry, ok := <-stdoutChan:
-				if !ok {
-					stdoutChan = nil
-					numChannels--
-					break
-				}
-				if err := stream.Send(&proto.AttachJobResponse{StreamEntry: entry}); err != nil {
-					// Send detach event to close channels
-					s.jobManager.Detach(ctx, commonName, req.Id, subscriberID)
-				}
-			case entry, ok := <-stderrChan:
-				if !ok {
-					stderrChan = nil
-					numChannels--
-					break
-				}
-				if err := stream.Send(&proto.AttachJobResponse{StreamEntry: entry}); err != nil {
-					// Send detach event to close channels
-					s.jobManager.Detach(ctx, commonName, req.Id, subscriberID)
-				}
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			switch r := req.Request.(type) {
+			case *proto.InteractJobRequest_Stdin:
+				err = s.jobManager.WriteStdin(ctx, commonName, attach.Id, r.Stdin)
+			case *proto.InteractJobRequest_StdinEof:
+				err = s.jobManager.CloseStdin(ctx, commonName, attach.Id)
+			case *proto.InteractJobRequest_Resize:
+				err = s.jobManager.Resize(ctx, commonName, attach.Id, r.Resize)
+			default:
+				err = status.Errorf(codes.InvalidArgument, "unexpected request after attach")
+			}
+			if err != nil {
+				stdinErrChan <- err
+				cancel()
+				return
 			}
 		}
-		// If we are here, the channels have closed
-	}()
-	go func() {
-		<-ctx.Done()
-		// We are here as the client terminated the connection.
-		// We should detach to close the client channels
-		s.jobManager.Detach(ctx, commonName, req.Id, subscriberID)
 	}()
 
-	// We will wait for the client channels to close
-	wg.Wait()
+	// This blocks till the job output has been sent
+	// or the client terminated the connection
+	err = s.jobManager.Attach(ctx, commonName, attach.Id, attach.Offset,
+		attach.FromSequence, func(entry *proto.JobStreamEntry) error {
+			return stream.Send(&proto.InteractJobResponse{StreamEntry: entry})
+		})
+	select {
+	case stdinErr := <-stdinErrChan:
+		return stdinErr
+	default:
+		return err
+	}
+}
 
-	return nil
+func (s *Server) SignalJob(ctx context.Context,
+	req *proto.SignalJobRequest) (*proto.SignalJobResponse, error) {
+	err := s.jobManager.Signal(ctx, s.getCNFromCtx(ctx), req.Id, req.Signal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SignalJobResponse{}, nil
+}
+
+func (s *Server) PauseJob(ctx context.Context,
+	req *proto.PauseJobRequest) (*proto.PauseJobResponse, error) {
+	if err := s.jobManager.Pause(ctx, s.getCNFromCtx(ctx), req.Id); err != nil {
+		return nil, err
+	}
+
+	return &proto.PauseJobResponse{}, nil
+}
+
+func (s *Server) ResumeJob(ctx context.Context,
+	req *proto.ResumeJobRequest) (*proto.ResumeJobResponse, error) {
+	if err := s.jobManager.Resume(ctx, s.getCNFromCtx(ctx), req.Id); err != nil {
+		return nil, err
+	}
+
+	return &proto.ResumeJobResponse{}, nil
 }
 
 func (s *Server) TerminateJob(ctx context.Context,
 	req *proto.TerminateJobRequest) (*proto.TerminateJobResponse, error) {
-	err := s.jobManager.Terminate(ctx, s.getCNFromCtx(ctx), req.Id)
+	err := s.jobManager.Terminate(ctx, s.getCNFromCtx(ctx), req.Id, req.GracePeriod)
 	if err != nil {
 		return nil, err
 	}
@@ -167,8 +255,25 @@ func (s *Server) TerminateJob(ctx context.Context,
 }
 
 func (s *Server) createTLSTransportCredentials() (credentials.TransportCredentials, error) {
-	certPool, certificate, err := shared.LoadCertificates(s.config.CABundlePath,
-		s.config.CertPath, s.config.CertKeyPath)
+	tlsConfig, err := s.createTLSConfig(s.config.Load())
+	if err != nil {
+		return nil, err
+	}
+	s.tlsConfig.Store(tlsConfig)
+
+	// Every handshake picks the latest configuration so that
+	// reloaded certificates apply to new connections
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS13,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return s.tlsConfig.Load(), nil
+		},
+	}), nil
+}
+
+func (s *Server) createTLSConfig(config *Config) (*tls.Config, error) {
+	certPool, certificate, err := shared.LoadCertificates(config.CABundlePath,
+		config.CertPath, config.CertKeyPath)
 	if err != nil {
 		return nil, err
 	}
@@ -179,9 +284,11 @@ func (s *Server) createTLSTransportCredentials() (credentials.TransportCredentia
 		ClientAuth:               tls.RequireAndVerifyClientCert,
 		Certificates:             []tls.Certificate{*certificate},
 		ClientCAs:                certPool,
+		// Returned from GetConfigForClient, thus needs own ALPN for gRPC
+		NextProtos: []string{"h2"},
 	}
 
-	return credentials.NewTLS(tlsConfig), nil
+	return tlsConfig, nil
 }
 
 func (s *Server) setPeerCertCNInCtx(ctx context.Context, req interface{},
@@ -197,15 +304,16 @@ func (s *Server) setPeerCertCNInCtx(ctx context.Context, req interface{},
 func (s *Server) getCNFromContext(ctx context.Context) (string, error) {
 	peer, ok := peer.FromContext(ctx)
 	if !ok {
-		return "", fmt.Errorf("failed getting remote peer")
+		return "", status.Errorf(codes.Unauthenticated, "failed getting remote peer")
 	}
 	tlsInfo, ok := peer.AuthInfo.(credentials.TLSInfo)
 	if !ok || tlsInfo.State.VerifiedChains == nil {
-		return "", fmt.Errorf("failed getting TLS info from remote peer")
+		return "", status.Errorf(codes.Unauthenticated,
+			"failed getting TLS info from remote peer")
 	}
 	commonName := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
 	if commonName == "" {
-		return "", fmt.Errorf("invalid CN received")
+		return "", status.Errorf(codes.Unauthenticated, "invalid CN received")
 	}
 
 	return commonName, nil
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/troplet/pkg/proto"
+)
+
+// Policy applied once the buffer of a subscriber is full
+type SlowSubscriberPolicy string
+
+const (
+	// Drop the oldest buffered entries and report the gap
+	DropOldestPolicy SlowSubscriberPolicy = "drop-oldest"
+	// Disconnect the subscriber
+	DisconnectPolicy SlowSubscriberPolicy = "disconnect"
+	// Block the job output till the subscriber catches up
+	BackpressurePolicy SlowSubscriberPolicy = "backpressure"
+)
+
+func (p SlowSubscriberPolicy) Validate() error {
+	switch p {
+	case DropOldestPolicy, DisconnectPolicy, BackpressurePolicy:
+		return nil
+	}
+
+	return fmt.Errorf("invalid slow subscriber policy %s", p)
+}
+
+// Subscriber buffers live output of a job for one attached client
+// so that a slow client does not block the job or other clients.
+type subscriber struct {
+	id       uint64
+	capacity int
+	policy   SlowSubscriberPolicy
+	// To protect all the fields below
+	lock    sync.Mutex
+	entries []*proto.JobStreamEntry
+	// Bytes dropped since the last entry taken, reported as a gap
+	droppedBytes int64
+	closed       bool
+	// Set if the subscriber got disconnected for being slow
+	err error
+	// Signaled when entries get added or the subscriber gets closed
+	dataReady chan struct{}
+	// Signaled when entries get taken or the subscriber gets closed
+	spaceReady chan struct{}
+}
+
+func newSubscriber(id uint64, capacity int, policy SlowSubscriberPolicy) *subscriber {
+	return &subscriber{id: id, capacity: capacity, policy: policy,
+		dataReady: make(chan struct{}, 1), spaceReady: make(chan struct{}, 1)}
+}
+
+// Adds an entry and returns number of bytes dropped as per policy.
+// Blocks only with backpressure policy while the buffer is full.
+func (s *subscriber) push(entry *proto.JobStreamEntry) int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for !s.closed && len(s.entries) >= s.capacity &&
+		s.policy == BackpressurePolicy {
+		s.lock.Unlock()
+		<-s.spaceReady
+		s.lock.Lock()
+	}
+	if s.closed {
+		return 0
+	}
+	var dropped int64
+	if len(s.entries) >= s.capacity {
+		switch s.policy {
+		case DisconnectPolicy:
+			// Clients can resume from the last received entry
+			s.err = status.Error(codes.ResourceExhausted,
+				"subscriber too slow, disconnected")
+			s.closeLocked()
+			return int64(len(entry.Entry))
+		default:
+			dropped = int64(len(s.entries[0].Entry))
+			s.entries[0] = nil
+			s.entries = s.entries[1:]
+			s.droppedBytes += dropped
+		}
+	}
+	s.entries = append(s.entries, entry)
+	signal(s.dataReady)
+
+	return dropped
+}
+
+// Returns the next entry, blocking till one is available. A gap
+// marker carrying dropped bytes precedes the entries after a drop.
+// Returns nil entry once closed and all the entries are taken.
+func (s *subscriber) next(ctx context.Context) (*proto.JobStreamEntry, error) {
+	for {
+		s.lock.Lock()
+		if s.err != nil {
+			err := s.err
+			s.lock.Unlock()
+			return nil, err
+		}
+		if s.droppedBytes != 0 {
+			entry := &proto.JobStreamEntry{DroppedBytes: s.droppedBytes}
+			s.droppedBytes = 0
+			s.lock.Unlock()
+			return entry, nil
+		}
+		if len(s.entries) != 0 {
+			entry := s.entries[0]
+			s.entries[0] = nil
+			s.entries = s.entries[1:]
+			signal(s.spaceReady)
+			s.lock.Unlock()
+			return entry, nil
+		}
+		closed := s.closed
+		s.lock.Unlock()
+		if closed {
+			return nil, nil
+		}
+		select {
+		case <-s.dataReady:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Closes the subscriber. Entries already buffered can still be taken.
+func (s *subscriber) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closeLocked()
+}
+
+func (s *subscriber) closeLocked() {
+	s.closed = true
+	signal(s.dataReady)
+	signal(s.spaceReady)
+}
+
+// Non blocking notification on a channel of capacity one
+func signal(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
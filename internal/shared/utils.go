@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -16,6 +18,11 @@ import (
 type Logger = *zap.SugaredLogger
 
 func CreateLogger() Logger {
+	return CreateLoggerWithLevel(zap.NewAtomicLevelAt(zap.DebugLevel))
+}
+
+// Creates logger whose level can be changed at runtime
+func CreateLoggerWithLevel(level zap.AtomicLevel) Logger {
 	// Logger configuration to include time, level and message
 	encoderCfg := zapcore.EncoderConfig{
 		TimeKey:    "time",
@@ -31,8 +38,7 @@ func CreateLogger() Logger {
 	core := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(encoderCfg),
 		zapcore.Lock(os.Stdout),
-		// TODO: Candidate for config
-		zap.NewAtomicLevelAt(zap.DebugLevel),
+		level,
 	)
 
 	// Build the logger with this core
@@ -102,3 +108,25 @@ func ReadFile(path string) ([]byte, error) {
 
 	return content, err
 }
+
+// Registers callback to be invoked once SIGINT or SIGTERM is received
+func RegisterShutdownSigCallback(callback func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		signal.Stop(sigChan)
+		callback()
+	}()
+}
+
+// Registers callback to be invoked every time SIGHUP is received
+func RegisterReloadSigCallback(callback func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	go func() {
+		for range sigChan {
+			callback()
+		}
+	}()
+}
@@ -1,12 +1,22 @@
 package cgroups
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// Time given to the processes of a control group to get frozen or thawed
+	freezeTimeout = 5 * time.Second
+	// Time given to the killed processes of a control group to exit
+	killTimeout = 5 * time.Second
 )
 
 // ControlGroup is a contract between ControlGroupsManager
@@ -21,52 +31,91 @@ type ControlGroupsManager struct {
 	cgroupFile       *os.File
 	cgroupPath       string
 	supportedCGroups []string
+	// Fail on limits of controllers that are not enabled
+	strict bool
+	fs     FS
 }
 
-func NewControlGroupsManager(name string) (*ControlGroupsManager, error) {
-	// Get cgroups path from /proc/mounts
-	cgroupV2Path, err := findCGroupV2Mount()
+// Returns manager of the control group with given name under the
+// parent group, or under cgroup2 mount root if parent is nil. The
+// group is managed on the filesystem of the parent, options apply
+// only without parent.
+func NewControlGroupsManager(name string, parent *ParentGroup,
+	opts ...Option) (*ControlGroupsManager, error) {
+	if parent != nil {
+		return &ControlGroupsManager{
+			cgroupPath:       filepath.Join(parent.path, name),
+			supportedCGroups: parent.controllers, strict: parent.strict,
+			cgroups: []ControlGroup{}, fs: parent.fs}, nil
+	}
+	fs := getOptions(opts).fs
+	cgroupV2Path, err := fs.GetMountPath()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cgroups path: %w", err)
 	}
 	// Get all the enabled controllers
-	supportedCGroups, err := readSubtreeControls(cgroupV2Path)
+	supportedCGroups, err := readSubtreeControls(fs, cgroupV2Path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get supported cgroups: %w", err)
 	}
 
 	return &ControlGroupsManager{
 		cgroupPath:       filepath.Join(cgroupV2Path, name),
-		supportedCGroups: supportedCGroups, cgroups: []ControlGroup{}}, nil
+		supportedCGroups: supportedCGroups, cgroups: []ControlGroup{}, fs: fs}, nil
 }
 
 func (m *ControlGroupsManager) NewCPUControlGroup(quotaMillSeconds,
 	periodMillSeconds int64) *CPUControlGroup {
 	cpu := NewCPUControlGroup(m.cgroupPath, quotaMillSeconds, periodMillSeconds)
+	cpu.fs = m.fs
+	m.cgroups = append(m.cgroups, cpu)
+
+	return cpu
+}
+
+func (m *ControlGroupsManager) NewCPUWeightControlGroup(weight int64) *CPUControlGroup {
+	cpu := NewCPUWeightControlGroup(m.cgroupPath, weight)
+	cpu.fs = m.fs
 	m.cgroups = append(m.cgroups, cpu)
 
 	return cpu
 }
 
-func (m *ControlGroupsManager) NewMemoryControlGroup(memoryKB int64) *MemoryControlGroup {
-	mem := NewMemoryControlGroup(m.cgroupPath, memoryKB)
+func (m *ControlGroupsManager) NewCPUSetControlGroup(cpus, mems string) *CPUSetControlGroup {
+	cpuset := NewCPUSetControlGroup(m.cgroupPath, cpus, mems)
+	cpuset.fs = m.fs
+	m.cgroups = append(m.cgroups, cpuset)
+
+	return cpuset
+}
+
+func (m *ControlGroupsManager) NewMemoryControlGroup(spec MemorySpec) *MemoryControlGroup {
+	mem := NewMemoryControlGroup(m.cgroupPath, spec)
+	mem.fs = m.fs
 	m.cgroups = append(m.cgroups, mem)
 
 	return mem
 }
 
-func (m *ControlGroupsManager) NewIOControlGroup(deviceMajorNum, deviceMinorNum int32,
-	rbps, wbps int64) *IOControlGroup {
-	io := NewIOControlGroup(m.cgroupPath, deviceMajorNum, deviceMinorNum, rbps, wbps)
+func (m *ControlGroupsManager) NewIOControlGroup(spec IOSpec) *IOControlGroup {
+	io := NewIOControlGroup(m.cgroupPath, spec)
+	io.fs = m.fs
 	m.cgroups = append(m.cgroups, io)
 
 	return io
 }
 
+func (m *ControlGroupsManager) NewPIDsControlGroup(maxPIDs int64) *PIDsControlGroup {
+	pids := NewPIDsControlGroup(m.cgroupPath, maxPIDs)
+	pids.fs = m.fs
+	m.cgroups = append(m.cgroups, pids)
+
+	return pids
+}
+
 func (m *ControlGroupsManager) Set() error {
-	if err := os.Mkdir(m.cgroupPath, 0755); err != nil {
-		return fmt.Errorf("failed to create cgroup path %s: %w",
-			m.cgroupPath, err)
+	if err := m.fs.Mkdir(m.cgroupPath); err != nil {
+		return err
 	}
 	for _, cgroup := range m.cgroups {
 		// My environment somehow does not have "io" controller
@@ -78,6 +127,9 @@ func (m *ControlGroupsManager) Set() error {
 		// Instead of failing the entire set, logging
 		// this and continuing
 		if !slices.Contains(m.supportedCGroups, cgroup.GetName()) {
+			if m.strict {
+				return fmt.Errorf("%s control group is not enabled", cgroup.GetName())
+			}
 			// Print this in red
 			fmt.Print("\033[31m" + cgroup.GetName() +
 				" control group is NOT enabled, continuing...\n" + "\033[0m")
@@ -91,75 +143,190 @@ func (m *ControlGroupsManager) Set() error {
 	return nil
 }
 
+// Kills all the processes in the control group, including the ones
+// that changed their process group or session. Falls back to killing
+// the processes listed in cgroup.procs if cgroup.kill is not supported.
+func (m *ControlGroupsManager) Kill() error {
+	err := m.fs.WriteFile(filepath.Join(m.cgroupPath, "cgroup.kill"), "1")
+	if err == nil {
+		return nil
+	}
+	// cgroup.kill is available since Linux 5.14
+	if killErr := m.killProcs(); killErr != nil {
+		return errors.Join(err, killErr)
+	}
+
+	return nil
+}
+
+// Kills the processes listed in cgroup.procs. The group is frozen
+// meanwhile, if possible, so that no new process gets forked.
+func (m *ControlGroupsManager) killProcs() error {
+	if m.Freeze() == nil {
+		// Killed processes exit once thawed
+		defer m.Thaw()
+	}
+	procsPath := filepath.Join(m.cgroupPath, "cgroup.procs")
+	content, err := m.fs.ReadFile(procsPath)
+	if err != nil {
+		return err
+	}
+	for _, field := range strings.Fields(string(content)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return fmt.Errorf("failed parsing %s: %w", procsPath, err)
+		}
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil &&
+			!errors.Is(err, syscall.ESRCH) {
+			return fmt.Errorf("failed killing process %d: %w", pid, err)
+		}
+	}
+
+	return nil
+}
+
+// Blocks till cgroup.events reports no process left in the control group
+func (m *ControlGroupsManager) waitUnpopulated() error {
+	eventsPath := filepath.Join(m.cgroupPath, "cgroup.events")
+	deadline := time.Now().Add(killTimeout)
+	for {
+		events, err := readKeyValues(m.fs, eventsPath)
+		if err != nil {
+			return err
+		}
+		if events["populated"] == "0" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for processes in %s to exit", m.cgroupPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Freezes all the processes in the control group. Blocks till
+// cgroup.events reports the group frozen.
+func (m *ControlGroupsManager) Freeze() error {
+	return m.setFrozen(true)
+}
+
+// Thaws all the processes in the control group. Blocks till
+// cgroup.events reports the group not frozen.
+func (m *ControlGroupsManager) Thaw() error {
+	return m.setFrozen(false)
+}
+
+func (m *ControlGroupsManager) setFrozen(frozen bool) error {
+	value := "0"
+	if frozen {
+		value = "1"
+	}
+	if err := m.fs.WriteFile(filepath.Join(m.cgroupPath, "cgroup.freeze"), value); err != nil {
+		return err
+	}
+	eventsPath := filepath.Join(m.cgroupPath, "cgroup.events")
+	deadline := time.Now().Add(freezeTimeout)
+	for {
+		events, err := readKeyValues(m.fs, eventsPath)
+		if err != nil {
+			return err
+		}
+		if events["frozen"] == value {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for frozen %s in %s", value, eventsPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Returns number of processes in the control group killed by
+// the OOM killer
+func (m *ControlGroupsManager) GetOOMKills() (uint64, error) {
+	memoryEvents, err := readUintKeyValues(m.fs, filepath.Join(m.cgroupPath, "memory.events"))
+	if err != nil {
+		return 0, err
+	}
+
+	return memoryEvents["oom_kill"], nil
+}
+
 func (m *ControlGroupsManager) GetControlGroupsFD() (int, error) {
 	if m.cgroupFile != nil {
 		return int(m.cgroupFile.Fd()), nil
 	}
-	cgroupFile, err := os.Open(m.cgroupPath)
+	cgroupFile, err := m.fs.OpenFile(m.cgroupPath, os.O_RDONLY)
 	if err != nil {
-		return 0, fmt.Errorf("failed to open %s: %w", m.cgroupPath, err)
+		return 0, err
 	}
 	m.cgroupFile = cgroupFile
 
 	return int(m.cgroupFile.Fd()), nil
 }
 
-func (m *ControlGroupsManager) Finish() {
-	if m.cgroupPath != "" {
-		os.RemoveAll(m.cgroupPath)
-	}
+// Removes the control group. Processes still left in the group,
+// such as orphaned descendants of the command, are killed first.
+func (m *ControlGroupsManager) Finish() error {
 	if m.cgroupFile != nil {
 		m.cgroupFile.Close()
+		m.cgroupFile = nil
+	}
+	if m.cgroupPath == "" {
+		return nil
+	}
+	events, err := readKeyValues(m.fs, filepath.Join(m.cgroupPath, "cgroup.events"))
+	if errors.Is(err, os.ErrNotExist) {
+		// Never got created
+		return nil
 	}
-}
-
-func findCGroupV2Mount() (string, error) {
-	f, err := os.Open("/proc/mounts")
 	if err != nil {
-		return "", err
+		return err
 	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		fields := strings.Split(scanner.Text(), " ")
-		if len(fields) >= 3 && fields[2] == "cgroup2" {
-			return fields[1], nil
+	if events["populated"] != "0" {
+		if err := m.Kill(); err != nil {
+			return err
+		}
+		if err := m.waitUnpopulated(); err != nil {
+			return err
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return "", err
-	}
+	return m.fs.Remove(m.cgroupPath)
+}
 
-	return "", os.ErrNotExist
+// Whether processes can be started right into the control group,
+// otherwise these must be added with AddProcess once started
+func (m *ControlGroupsManager) CanCloneInto() bool {
+	return m.fs.CanCloneInto()
 }
 
-func readSubtreeControls(cgroupPath string) ([]string, error) {
-	filePath := cgroupPath + "/cgroup.subtree_control"
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-	ret := []string{}
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		ret = append(ret, strings.Split(line, " ")...)
-	}
+// Moves the process with given pid into the control group
+func (m *ControlGroupsManager) AddProcess(pid int) error {
+	return m.fs.WriteFile(filepath.Join(m.cgroupPath, "cgroup.procs"), strconv.Itoa(pid))
+}
 
-	if err := scanner.Err(); err != nil {
+func readSubtreeControls(fs FS, cgroupPath string) ([]string, error) {
+	content, err := fs.ReadFile(filepath.Join(cgroupPath, "cgroup.subtree_control"))
+	if err != nil {
 		return nil, err
 	}
 
-	return ret, nil
+	return strings.Fields(string(content)), nil
 }
 
-func writeToFile(filePath, value string) error {
-	if err := os.WriteFile(filePath, []byte(value), 0644); err != nil {
-		return fmt.Errorf("failed writing to %s: %w", filePath, err)
+// Reads a flat keyed file such as cgroup.events
+func readKeyValues(fs FS, filePath string) (map[string]string, error) {
+	content, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		if key, value, found := strings.Cut(line, " "); found {
+			ret[key] = value
+		}
 	}
 
-	return nil
+	return ret, nil
 }
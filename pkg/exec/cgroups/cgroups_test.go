@@ -1,21 +1,50 @@
 package cgroups
 
 import (
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
+
+	"github.com/troplet/internal/cgroupstest"
 )
 
+// Returns fake cgroup2 filesystem with the default controllers
+// available in its root
+func newFakeFS(t *testing.T) *cgroupstest.FakeFS {
+	fs, err := cgroupstest.NewFakeFS(t.TempDir(), DefaultControllers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return fs
+}
+
 func TestCGroups(t *testing.T) {
-	cgroupsMgr, err := NewControlGroupsManager(uuid.New().String())
+	fs := newFakeFS(t)
+	root, _ := fs.GetMountPath()
+	// Controllers must be enabled in the root to be supported
+	if err := fs.WriteFile(filepath.Join(root, "cgroup.subtree_control"),
+		"+cpu +memory +pids"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	name := uuid.New().String()
+	cgroupsMgr, err := NewControlGroupsManager(name, nil, WithFS(fs))
 	if diff := cmp.Diff(nil, err); diff != "" {
 		t.Errorf("Unexpected result: %s", diff)
 	}
 	cgroupsMgr.NewCPUControlGroup(50, 1000)
-	cgroupsMgr.NewMemoryControlGroup(16 * 1024)
+	cgroupsMgr.NewCPUWeightControlGroup(200)
+	swapMaxKB := int64(0)
+	cgroupsMgr.NewMemoryControlGroup(MemorySpec{MaxKB: 16 * 1024, HighKB: 12 * 1024,
+		LowKB: 8 * 1024, MinKB: 4 * 1024, SwapMaxKB: &swapMaxKB, OOMGroup: true})
+	cgroupsMgr.NewPIDsControlGroup(64)
 	err = cgroupsMgr.Set()
 	if diff := cmp.Diff(nil, err); diff != "" {
 		t.Errorf("Unexpected result: %s", diff)
@@ -28,6 +57,13 @@ func TestCGroups(t *testing.T) {
 	if diff := cmp.Diff("50000 1000000\n", string(content)); diff != "" {
 		t.Errorf("Unexpected result: %s", diff)
 	}
+	content, err = os.ReadFile(filepath.Join(cgroupsMgr.cgroupPath, "cpu.weight"))
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("200\n", string(content)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
 	// memory
 	content, err = os.ReadFile(filepath.Join(cgroupsMgr.cgroupPath, "memory.max"))
 	if diff := cmp.Diff(nil, err); diff != "" {
@@ -36,5 +72,267 @@ func TestCGroups(t *testing.T) {
 	if diff := cmp.Diff("16777216\n", string(content)); diff != "" {
 		t.Errorf("Unexpected result: %s", diff)
 	}
-	cgroupsMgr.Finish()
+	content, err = os.ReadFile(filepath.Join(cgroupsMgr.cgroupPath, "memory.high"))
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("12582912\n", string(content)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// pids
+	content, err = os.ReadFile(filepath.Join(cgroupsMgr.cgroupPath, "pids.max"))
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("64\n", string(content)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if err := cgroupsMgr.Finish(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]cgroupstest.FakeWrite{
+		{Path: "cgroup.subtree_control", Value: "+cpu +memory +pids"},
+		{Path: name + "/cpu.max", Value: "50000 1000000"},
+		{Path: name + "/cpu.weight", Value: "200"},
+		{Path: name + "/memory.min", Value: "4194304"},
+		{Path: name + "/memory.low", Value: "8388608"},
+		{Path: name + "/memory.high", Value: "12582912"},
+		{Path: name + "/memory.max", Value: "16777216"},
+		{Path: name + "/memory.swap.max", Value: "0"},
+		{Path: name + "/memory.oom.group", Value: "1"},
+		{Path: name + "/pids.max", Value: "64"},
+	}, fs.Writes()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if _, err := os.Stat(cgroupsMgr.cgroupPath); !os.IsNotExist(err) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParentGroup(t *testing.T) {
+	fs := newFakeFS(t)
+	parent, err := NewParentGroup("troplet.slice/jobs", DefaultControllers, false,
+		WithFS(fs))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(DefaultControllers, parent.GetControllers()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	cgroupsMgr, err := NewControlGroupsManager(uuid.New().String(), parent)
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(parent.GetPath(), filepath.Dir(cgroupsMgr.cgroupPath)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	err = cgroupsMgr.Set()
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Only the enabled controllers are available to the child
+	content, err := fs.ReadFile(filepath.Join(cgroupsMgr.cgroupPath, "cgroup.controllers"))
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(parent.GetControllers(), strings.Fields(string(content)),
+		cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if err := cgroupsMgr.Finish(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParentGroupPath(t *testing.T) {
+	fs := newFakeFS(t)
+	mountPath, err := fs.GetMountPath()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := []struct {
+		name       string
+		parent     string
+		expectPath string
+		expectErr  bool
+	}{
+		{"Relative", "troplet.slice", filepath.Join(mountPath, "troplet.slice"), false},
+		{"Absolute under mount", filepath.Join(mountPath, "troplet.slice/jobs"),
+			filepath.Join(mountPath, "troplet.slice/jobs"), false},
+		{"Absolute outside mount", "/tmp/troplet.slice", "", true},
+		{"Mount root", mountPath, "", true},
+		{"Relative outside mount", "../troplet.slice", "", true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		parent, err := NewParentGroup(test.parent, DefaultControllers, false, WithFS(fs))
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if err == nil {
+			if diff := cmp.Diff(test.expectPath, parent.GetPath()); diff != "" {
+				t.Errorf("Unexpected result: %s", diff)
+			}
+		}
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	tests := []struct {
+		name      string
+		list      string
+		expect    []int
+		expectErr bool
+	}{
+		{"Empty", "", []int{}, false},
+		{"Single", "3\n", []int{3}, false},
+		{"Ranges", "4-5,0-2,1", []int{0, 1, 2, 4, 5}, false},
+		{"Reversed range", "3-1", nil, true},
+		{"Negative", "-1", nil, true},
+		{"Not a number", "a", nil, true},
+		{"Highest number", "8190-8191", []int{8190, 8191}, false},
+		{"Huge range", "0-4000000000", nil, true},
+		{"Beyond highest number", "8192", nil, true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		list, err := ParseCPUList(test.list)
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expect, list); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+}
+
+func TestCPUControlGroup(t *testing.T) {
+	cgroupPath := t.TempDir()
+	if err := NewCPUControlGroup(cgroupPath, 50, 100).Set(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(cgroupPath, "cpu.max"))
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("50000 100000", string(content)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Values that would overflow once converted to microseconds
+	for _, cpu := range []*CPUControlGroup{
+		NewCPUControlGroup(cgroupPath, math.MaxInt64/2, 1000),
+		NewCPUControlGroup(cgroupPath, 1, math.MaxInt64/2),
+	} {
+		if err := cpu.Set(); err == nil {
+			t.Errorf("Expected error for cpu.max out of range")
+		}
+	}
+}
+
+func TestIOControlGroup(t *testing.T) {
+	// Control files are written as regular files, each write replacing
+	// the previous one
+	cgroupPath := t.TempDir()
+	io := NewIOControlGroup(cgroupPath, IOSpec{Devices: []IODeviceLimit{{
+		DeviceMajorNum: 259, DeviceMinorNum: 1048576,
+		ReadBps: 1024, ReadIOPS: 100, WriteIOPS: 50, Weight: 200}}})
+	if err := io.Set(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for file, expect := range map[string]string{
+		"io.max":    "259:1048576 rbps=1024 riops=100 wiops=50",
+		"io.weight": "259:1048576 200",
+	} {
+		content, err := os.ReadFile(filepath.Join(cgroupPath, file))
+		if diff := cmp.Diff(nil, err); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		if diff := cmp.Diff(expect, string(content)); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+	io = NewIOControlGroup(cgroupPath, IOSpec{Weight: MaxIOWeight + 1})
+	if err := io.Set(); err == nil {
+		t.Errorf("Expected error for weight out of range")
+	}
+}
+
+func TestPressureThresholdValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold PressureThreshold
+		expectErr bool
+	}{
+		{"Valid", PressureThreshold{Resource: PressureIO, Stall: time.Second,
+			Window: 4 * time.Second}, false},
+		{"Unknown resource", PressureThreshold{Resource: "net", Stall: time.Second,
+			Window: 2 * time.Second}, true},
+		{"Window too short", PressureThreshold{Resource: PressureCPU,
+			Stall: 100 * time.Millisecond, Window: 100 * time.Millisecond}, true},
+		{"Window too long", PressureThreshold{Resource: PressureCPU, Stall: time.Second,
+			Window: time.Minute}, true},
+		// Allowed only with CAP_SYS_RESOURCE
+		{"Window not a multiple of 2s", PressureThreshold{Resource: PressureCPU,
+			Stall: time.Second, Window: 3 * time.Second}, !hasSysResource()},
+		{"Stall beyond window", PressureThreshold{Resource: PressureMemory,
+			Stall: 3 * time.Second, Window: 2 * time.Second}, true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		err := test.threshold.Validate()
+		if diff := cmp.Diff(test.expectErr, err != nil); diff != "" {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestPressure(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "memory.pressure")
+	if err := os.WriteFile(filePath, []byte(
+		"some avg10=1.50 avg60=0.25 avg300=0.00 total=1200\n"+
+			"full avg10=0.75 avg60=0.00 avg300=0.00 total=600\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pressure, err := readPressure(realFS{}, filePath)
+	if diff := cmp.Diff(nil, err); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(Pressure{
+		Some: PressureData{Avg10: 1.5, Avg60: 0.25, TotalUsec: 1200},
+		Full: PressureData{Avg10: 0.75, TotalUsec: 600}}, pressure); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Trigger gets registered on a control group and closing it
+	// wakes up the waiter
+	cgroupsMgr, err := NewControlGroupsManager(uuid.New().String(), nil,
+		WithFS(newFakeFS(t)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cgroupsMgr.Set(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cgroupsMgr.Finish()
+	_, err = cgroupsMgr.NewPressureTrigger(PressureThreshold{Resource: PressureCPU,
+		Stall: time.Second, Window: time.Minute})
+	if err == nil {
+		t.Errorf("Expected error for window out of range")
+	}
+	trigger, err := cgroupsMgr.NewPressureTrigger(PressureThreshold{Resource: PressureCPU,
+		Stall: 100 * time.Millisecond, Window: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := make(chan bool)
+	go func() {
+		fired, _ := trigger.Wait()
+		result <- fired
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := trigger.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(false, <-result); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
 }
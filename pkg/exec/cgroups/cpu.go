@@ -3,18 +3,43 @@ package cgroups
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
+)
+
+// Range of cpu.weight, the kernel default being 100
+const (
+	MinCPUWeight = 1
+	MaxCPUWeight = 10000
+)
+
+// Range of cpu.max in milliseconds. The kernel takes a period between
+// 1ms and 1s and a quota of at most 2^44-1us, so either fits in int64
+// once converted to microseconds
+const (
+	MinCPUPeriodMs = 1
+	MaxCPUPeriodMs = 1000
+	MaxCPUQuotaMs  = (1<<44 - 1) / 1000
 )
 
 type CPUControlGroup struct {
 	quotaMillSeconds  int64
 	periodMillSeconds int64
-	cgroupPath        string
+	// Share relative to the siblings, not set if zero
+	weight     int64
+	cgroupPath string
+	fs         FS
 }
 
 func NewCPUControlGroup(cgroupPath string, quotaMillSeconds,
 	periodMillSeconds int64) *CPUControlGroup {
-	return &CPUControlGroup{quotaMillSeconds, periodMillSeconds, cgroupPath}
+	return &CPUControlGroup{quotaMillSeconds: quotaMillSeconds,
+		periodMillSeconds: periodMillSeconds, cgroupPath: cgroupPath, fs: realFS{}}
+}
 
+// Returns CPU control group setting only cpu.weight for proportional
+// sharing of CPU between the groups under the same parent
+func NewCPUWeightControlGroup(cgroupPath string, weight int64) *CPUControlGroup {
+	return &CPUControlGroup{weight: weight, cgroupPath: cgroupPath, fs: realFS{}}
 }
 
 func (c *CPUControlGroup) GetName() string {
@@ -23,10 +48,28 @@ func (c *CPUControlGroup) GetName() string {
 
 func (c *CPUControlGroup) Set() error {
 	if c.quotaMillSeconds != 0 && c.periodMillSeconds != 0 {
+		if c.quotaMillSeconds < 0 || c.quotaMillSeconds > MaxCPUQuotaMs {
+			return fmt.Errorf("cpu quota %dms out of range [0, %d]",
+				c.quotaMillSeconds, MaxCPUQuotaMs)
+		}
+		if c.periodMillSeconds < MinCPUPeriodMs || c.periodMillSeconds > MaxCPUPeriodMs {
+			return fmt.Errorf("cpu period %dms out of range [%d, %d]",
+				c.periodMillSeconds, MinCPUPeriodMs, MaxCPUPeriodMs)
+		}
 		target := filepath.Join(c.cgroupPath, "cpu.max")
 		value := fmt.Sprintf("%d %d", c.quotaMillSeconds*1000,
 			c.periodMillSeconds*1000)
-		if err := writeToFile(target, value); err != nil {
+		if err := c.fs.WriteFile(target, value); err != nil {
+			return err
+		}
+	}
+	if c.weight != 0 {
+		if c.weight < MinCPUWeight || c.weight > MaxCPUWeight {
+			return fmt.Errorf("cpu weight %d out of range [%d, %d]",
+				c.weight, MinCPUWeight, MaxCPUWeight)
+		}
+		target := filepath.Join(c.cgroupPath, "cpu.weight")
+		if err := c.fs.WriteFile(target, strconv.FormatInt(c.weight, 10)); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,111 @@
+package cgroups
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CPUSetControlGroup pins the processes of the group to given
+// CPUs and memory nodes
+type CPUSetControlGroup struct {
+	// Lists such as "0-3,6", not set if empty
+	cpus       string
+	mems       string
+	cgroupPath string
+	fs         FS
+}
+
+func NewCPUSetControlGroup(cgroupPath, cpus, mems string) *CPUSetControlGroup {
+	return &CPUSetControlGroup{cpus, mems, cgroupPath, realFS{}}
+}
+
+func (c *CPUSetControlGroup) GetName() string {
+	return "cpuset"
+}
+
+// Writes cpuset.cpus and cpuset.mems, after checking these are within
+// the effective ones of the parent since the kernel would otherwise
+// silently narrow them down
+func (c *CPUSetControlGroup) Set() error {
+	parentPath := filepath.Dir(c.cgroupPath)
+	for _, v := range []struct {
+		name  string
+		value string
+	}{
+		{"cpuset.cpus", c.cpus},
+		{"cpuset.mems", c.mems},
+	} {
+		if v.value == "" {
+			continue
+		}
+		requested, err := ParseCPUList(v.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", v.name, err)
+		}
+		effectivePath := filepath.Join(parentPath, v.name+".effective")
+		content, err := c.fs.ReadFile(effectivePath)
+		if err != nil {
+			return err
+		}
+		effective, err := ParseCPUList(string(content))
+		if err != nil {
+			return fmt.Errorf("failed parsing %s: %w", effectivePath, err)
+		}
+		for _, n := range requested {
+			if !slices.Contains(effective, n) {
+				return fmt.Errorf("%s %s not within %s of parent",
+					v.name, v.value, strings.TrimSpace(string(content)))
+			}
+		}
+		if err := c.fs.WriteFile(filepath.Join(c.cgroupPath, v.name), v.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Highest CPU or memory node number accepted, the kernel supports
+// at most 8192 CPUs
+const MaxCPUListNumber = 8191
+
+// Parses a list of CPUs or memory nodes in cpuset format, comma
+// separated numbers and inclusive ranges such as "0-3,6". Returns
+// the numbers sorted without duplicates. Numbers above
+// MaxCPUListNumber are rejected before expanding the ranges.
+func ParseCPUList(list string) ([]int, error) {
+	ret := []int{}
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return ret, nil
+	}
+	// Repeated ranges are counted once
+	var present [MaxCPUListNumber + 1]bool
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil || start < 0 || start > MaxCPUListNumber {
+			return nil, fmt.Errorf("invalid entry %q in %q", part, list)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil || end < start ||
+				end > MaxCPUListNumber {
+				return nil, fmt.Errorf("invalid range %q in %q", part, list)
+			}
+		}
+		for n := start; n <= end; n++ {
+			present[n] = true
+		}
+	}
+	for n, found := range present {
+		if found {
+			ret = append(ret, n)
+		}
+	}
+
+	return ret, nil
+}
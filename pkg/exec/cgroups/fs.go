@@ -0,0 +1,125 @@
+package cgroups
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FS is the cgroup2 filesystem the control groups are managed on.
+// The real one is used by default, a fake allows managing control
+// groups without privileges, such as in tests.
+type FS interface {
+	// Returns mount point of cgroup2
+	GetMountPath() (string, error)
+	ReadFile(filePath string) ([]byte, error)
+	WriteFile(filePath, value string) error
+	Mkdir(dirPath string) error
+	// Removes a control group, which must have no processes or
+	// children left
+	Remove(dirPath string) error
+	// Opens a file or directory as is, such as the directory handed
+	// to clone or a pressure file to register a trigger on
+	OpenFile(filePath string, flag int) (*os.File, error)
+	// Whether processes can be started right into a control group
+	// with CLONE_INTO_CGROUP. Otherwise these are attached by writing
+	// to cgroup.procs once started.
+	CanCloneInto() bool
+}
+
+// Option of the control groups managed
+type Option func(*options)
+
+type options struct {
+	fs FS
+}
+
+// Option to manage the control groups on given filesystem instead
+// of the real one
+func WithFS(fs FS) Option {
+	return func(o *options) {
+		o.fs = fs
+	}
+}
+
+func getOptions(opts []Option) *options {
+	ret := &options{fs: realFS{}}
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	return ret
+}
+
+// Real cgroup2 filesystem found in /proc/mounts
+type realFS struct{}
+
+func (realFS) GetMountPath() (string, error) {
+	f, err := os.Open("/proc/mounts")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), " ")
+		if len(fields) >= 3 && fields[2] == "cgroup2" {
+			return fields[1], nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", os.ErrNotExist
+}
+
+func (realFS) ReadFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", filePath, err)
+	}
+
+	return content, nil
+}
+
+func (realFS) WriteFile(filePath, value string) error {
+	if err := os.WriteFile(filePath, []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed writing to %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+func (realFS) Mkdir(dirPath string) error {
+	if err := os.Mkdir(dirPath, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup path %s: %w", dirPath, err)
+	}
+
+	return nil
+}
+
+func (realFS) Remove(dirPath string) error {
+	// Control files of cgroupfs go away along with the directory
+	if err := os.Remove(dirPath); err != nil {
+		return fmt.Errorf("failed to remove cgroup path %s: %w", dirPath, err)
+	}
+
+	return nil
+}
+
+func (realFS) OpenFile(filePath string, flag int) (*os.File, error) {
+	file, err := os.OpenFile(filePath, flag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+
+	return file, nil
+}
+
+func (realFS) CanCloneInto() bool {
+	return true
+}
@@ -3,19 +3,44 @@ package cgroups
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
+// Range of io.weight, the kernel default being 100
+const (
+	MinIOWeight = 1
+	MaxIOWeight = 10000
+)
+
+// IOSpec holds the IO controls of a control group
+type IOSpec struct {
+	// Weight applied to the devices without their own weight,
+	// not set if zero
+	Weight  int64
+	Devices []IODeviceLimit
+}
+
+// IODeviceLimit holds the limits on a block device, zero
+// values are not set
+type IODeviceLimit struct {
+	DeviceMajorNum int32
+	DeviceMinorNum int32
+	ReadBps        int64
+	WriteBps       int64
+	ReadIOPS       int64
+	WriteIOPS      int64
+	Weight         int64
+}
+
 type IOControlGroup struct {
-	deviceMajorNum int32
-	deviceMinorNum int32
-	rbps           int64
-	wbps           int64
-	cgroupPath     string
+	spec       IOSpec
+	cgroupPath string
+	fs         FS
 }
 
-func NewIOControlGroup(cgroupPath string, deviceMajorNum, deviceMinorNum int32,
-	rbps, wbps int64) *IOControlGroup {
-	return &IOControlGroup{deviceMajorNum, deviceMinorNum, rbps, wbps, cgroupPath}
+func NewIOControlGroup(cgroupPath string, spec IOSpec) *IOControlGroup {
+	return &IOControlGroup{spec, cgroupPath, realFS{}}
 }
 
 func (c *IOControlGroup) GetName() string {
@@ -23,22 +48,52 @@ func (c *IOControlGroup) GetName() string {
 }
 
 func (c *IOControlGroup) Set() error {
-	if c.rbps != 0 {
-		target := filepath.Join(c.cgroupPath, "io.max")
-		value := fmt.Sprintf("%d:%d rbps=%d", c.deviceMajorNum,
-			c.deviceMinorNum, c.rbps)
-		if err := writeToFile(target, value); err != nil {
+	if c.spec.Weight != 0 {
+		if err := c.setWeight("default", c.spec.Weight); err != nil {
 			return err
 		}
 	}
-	if c.wbps != 0 {
-		target := filepath.Join(c.cgroupPath, "io.max")
-		value := fmt.Sprintf("%d:%d wbps=%d", c.deviceMajorNum,
-			c.deviceMinorNum, c.wbps)
-		if err := writeToFile(target, value); err != nil {
-			return err
+	for _, device := range c.spec.Devices {
+		deviceNum := fmt.Sprintf("%d:%d", device.DeviceMajorNum, device.DeviceMinorNum)
+		// All the limits of a device go in a single write
+		limits := []string{}
+		for _, v := range []struct {
+			key   string
+			value int64
+		}{
+			{"rbps", device.ReadBps},
+			{"wbps", device.WriteBps},
+			{"riops", device.ReadIOPS},
+			{"wiops", device.WriteIOPS},
+		} {
+			if v.value != 0 {
+				limits = append(limits, v.key+"="+strconv.FormatInt(v.value, 10))
+			}
+		}
+		if len(limits) != 0 {
+			target := filepath.Join(c.cgroupPath, "io.max")
+			value := deviceNum + " " + strings.Join(limits, " ")
+			if err := c.fs.WriteFile(target, value); err != nil {
+				return err
+			}
+		}
+		if device.Weight != 0 {
+			if err := c.setWeight(deviceNum, device.Weight); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
+
+// Writes io.weight of given device, or the default one
+func (c *IOControlGroup) setWeight(deviceNum string, weight int64) error {
+	if weight < MinIOWeight || weight > MaxIOWeight {
+		return fmt.Errorf("io weight %d out of range [%d, %d]",
+			weight, MinIOWeight, MaxIOWeight)
+	}
+
+	return c.fs.WriteFile(filepath.Join(c.cgroupPath, "io.weight"),
+		deviceNum+" "+strconv.FormatInt(weight, 10))
+}
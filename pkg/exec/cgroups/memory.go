@@ -5,13 +5,30 @@ import (
 	"strconv"
 )
 
+// Memory controls of a control group. Zero values are not set,
+// except for swap which is set only if non nil.
+type MemorySpec struct {
+	// Hard limit, processes are OOM-killed beyond it
+	MaxKB int64
+	// Soft limit, processes are throttled and reclaimed beyond it
+	HighKB int64
+	// Best effort and hard protection from reclaim
+	LowKB int64
+	MinKB int64
+	// Swap limit, zero disables swap
+	SwapMaxKB *int64
+	// Kill all the processes of the group together on OOM
+	OOMGroup bool
+}
+
 type MemoryControlGroup struct {
-	memKB      int64
+	spec       MemorySpec
 	cgroupPath string
+	fs         FS
 }
 
-func NewMemoryControlGroup(cgroupPath string, memKB int64) *MemoryControlGroup {
-	return &MemoryControlGroup{memKB, cgroupPath}
+func NewMemoryControlGroup(cgroupPath string, spec MemorySpec) *MemoryControlGroup {
+	return &MemoryControlGroup{spec, cgroupPath, realFS{}}
 }
 
 func (c *MemoryControlGroup) GetName() string {
@@ -19,10 +36,33 @@ func (c *MemoryControlGroup) GetName() string {
 }
 
 func (c *MemoryControlGroup) Set() error {
-	if c.memKB != 0 {
-		target := filepath.Join(c.cgroupPath, "memory.max")
-		if err := writeToFile(target,
-			strconv.FormatInt(c.memKB*1024, 10)); err != nil {
+	// Protections first so that these never exceed the limits meanwhile
+	for _, v := range []struct {
+		file string
+		kb   int64
+	}{
+		{"memory.min", c.spec.MinKB},
+		{"memory.low", c.spec.LowKB},
+		{"memory.high", c.spec.HighKB},
+		{"memory.max", c.spec.MaxKB},
+	} {
+		if v.kb != 0 {
+			target := filepath.Join(c.cgroupPath, v.file)
+			if err := c.fs.WriteFile(target, strconv.FormatInt(v.kb*1024, 10)); err != nil {
+				return err
+			}
+		}
+	}
+	if c.spec.SwapMaxKB != nil {
+		target := filepath.Join(c.cgroupPath, "memory.swap.max")
+		if err := c.fs.WriteFile(target,
+			strconv.FormatInt(*c.spec.SwapMaxKB*1024, 10)); err != nil {
+			return err
+		}
+	}
+	if c.spec.OOMGroup {
+		target := filepath.Join(c.cgroupPath, "memory.oom.group")
+		if err := c.fs.WriteFile(target, "1"); err != nil {
 			return err
 		}
 	}
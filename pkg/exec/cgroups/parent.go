@@ -0,0 +1,158 @@
+package cgroups
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Leaf the server is moved to if it lives in a group on the
+// chain to the parent group, since a group with processes
+// cannot enable controllers for its children
+const serverLeafName = "server"
+
+// Controllers enabled for the control groups of commands by default
+var DefaultControllers = []string{"cpu", "cpuset", "memory", "io", "pids"}
+
+// ParentGroup is a control group under which the control groups
+// of commands are created, with the controllers enabled for them.
+type ParentGroup struct {
+	path        string
+	controllers []string
+	// Fail on limits of controllers that are not enabled
+	strict bool
+	fs     FS
+}
+
+// Creates the parent group with given path relative to cgroup2 mount,
+// or absolute under the mount, and enables given controllers in subtree_control of every group on
+// the chain from the mount root down to it. In strict mode, fails if
+// any of the controllers cannot be enabled, otherwise the parent group
+// has only the controllers that could be enabled. Control groups of
+// commands under the parent group are managed on the same filesystem.
+func NewParentGroup(name string, controllers []string, strict bool,
+	opts ...Option) (*ParentGroup, error) {
+	fs := getOptions(opts).fs
+	cgroupV2Path, err := fs.GetMountPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cgroups path: %w", err)
+	}
+	path := filepath.Join(cgroupV2Path, name)
+	if filepath.IsAbs(name) {
+		path = filepath.Clean(name)
+	}
+	if !strings.HasPrefix(path, cgroupV2Path+"/") {
+		return nil, fmt.Errorf("invalid parent group %s", name)
+	}
+	// Walk down from the mount root, creating the missing groups and
+	// enabling in each group what its parent made available
+	groups := []string{cgroupV2Path}
+	for _, part := range strings.Split(strings.TrimPrefix(path, cgroupV2Path+"/"), "/") {
+		group := filepath.Join(groups[len(groups)-1], part)
+		if err := fs.Mkdir(group); err != nil && !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	var enableErrs []error
+	for i, group := range groups {
+		// Root group is exempt from the no internal process rule
+		if i != 0 {
+			if err := moveServerToLeaf(fs, group); err != nil {
+				enableErrs = append(enableErrs, err)
+			}
+		}
+		if err := enableControllers(fs, group, controllers); err != nil {
+			enableErrs = append(enableErrs, err)
+		}
+	}
+	enabled, err := readSubtreeControls(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled controllers: %w", err)
+	}
+	parent := &ParentGroup{path: path, strict: strict, fs: fs}
+	for _, controller := range controllers {
+		if slices.Contains(enabled, controller) {
+			parent.controllers = append(parent.controllers, controller)
+		}
+	}
+	if strict && len(parent.controllers) != len(controllers) {
+		return nil, fmt.Errorf("failed to enable controllers %v in %s: %w",
+			controllers, path, errors.Join(enableErrs...))
+	}
+
+	return parent, nil
+}
+
+// Returns path of the parent group
+func (p *ParentGroup) GetPath() string {
+	return p.path
+}
+
+// Returns controllers enabled for the control groups of commands
+func (p *ParentGroup) GetControllers() []string {
+	return p.controllers
+}
+
+// Enables the controllers available in the group, one at a time so
+// that one failing does not prevent the others
+func enableControllers(fs FS, group string, controllers []string) error {
+	available, err := readControllers(fs, filepath.Join(group, "cgroup.controllers"))
+	if err != nil {
+		return err
+	}
+	enabled, err := readSubtreeControls(fs, group)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, controller := range controllers {
+		if slices.Contains(enabled, controller) {
+			continue
+		}
+		if !slices.Contains(available, controller) {
+			errs = append(errs, fmt.Errorf("%s controller not available in %s",
+				controller, group))
+			continue
+		}
+		if err := fs.WriteFile(filepath.Join(group, "cgroup.subtree_control"),
+			"+"+controller); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Moves the server process to a leaf under the group if the server
+// is in the group. Other processes in the group are left as is.
+func moveServerToLeaf(fs FS, group string) error {
+	content, err := fs.ReadFile(filepath.Join(group, "cgroup.procs"))
+	if err != nil {
+		return err
+	}
+	pid := strconv.Itoa(os.Getpid())
+	if !slices.Contains(strings.Fields(string(content)), pid) {
+		return nil
+	}
+	leaf := filepath.Join(group, serverLeafName)
+	if err := fs.Mkdir(leaf); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	return fs.WriteFile(filepath.Join(leaf, "cgroup.procs"), pid)
+}
+
+// Reads space separated controller names
+func readControllers(fs FS, filePath string) ([]string, error) {
+	content, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(content)), nil
+}
@@ -0,0 +1,31 @@
+package cgroups
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+type PIDsControlGroup struct {
+	maxPIDs    int64
+	cgroupPath string
+	fs         FS
+}
+
+func NewPIDsControlGroup(cgroupPath string, maxPIDs int64) *PIDsControlGroup {
+	return &PIDsControlGroup{maxPIDs, cgroupPath, realFS{}}
+}
+
+func (c *PIDsControlGroup) GetName() string {
+	return "pids"
+}
+
+func (c *PIDsControlGroup) Set() error {
+	if c.maxPIDs != 0 {
+		target := filepath.Join(c.cgroupPath, "pids.max")
+		if err := c.fs.WriteFile(target, strconv.FormatInt(c.maxPIDs, 10)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package cgroups
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Resource with pressure stall information of a control group
+type PressureResource string
+
+const (
+	PressureCPU    PressureResource = "cpu"
+	PressureMemory PressureResource = "memory"
+	PressureIO     PressureResource = "io"
+)
+
+// Window limits of pressure triggers. Without CAP_SYS_RESOURCE the
+// kernel further requires the window to be a multiple of 2s.
+const (
+	MinPressureWindow          = 500 * time.Millisecond
+	MaxPressureWindow          = 10 * time.Second
+	UnprivilegedPressureWindow = 2 * time.Second
+)
+
+// Share of time in percent over the last 10s, 60s and 300s that
+// tasks were stalled, and the total stall time
+type PressureData struct {
+	Avg10     float64
+	Avg60     float64
+	Avg300    float64
+	TotalUsec uint64
+}
+
+// Pressure of a resource. Some is when at least one task is stalled
+// on the resource, full is when all the non-idle tasks are stalled.
+type Pressure struct {
+	Some PressureData
+	Full PressureData
+}
+
+// Stall time within a window beyond which a pressure trigger fires
+type PressureThreshold struct {
+	Resource PressureResource
+	// Full stall instead of some
+	Full   bool
+	Stall  time.Duration
+	Window time.Duration
+}
+
+func (t PressureThreshold) String() string {
+	kind := "some"
+	if t.Full {
+		kind = "full"
+	}
+
+	return fmt.Sprintf("%s %s %s/%s", t.Resource, kind, t.Stall, t.Window)
+}
+
+// Validates the threshold against the limits of the kernel
+func (t PressureThreshold) Validate() error {
+	switch t.Resource {
+	case PressureCPU, PressureMemory, PressureIO:
+	default:
+		return fmt.Errorf("invalid pressure resource %q", t.Resource)
+	}
+	if t.Window < MinPressureWindow || t.Window > MaxPressureWindow {
+		return fmt.Errorf("pressure window %s out of range [%s, %s]", t.Window,
+			MinPressureWindow, MaxPressureWindow)
+	}
+	if t.Window%UnprivilegedPressureWindow != 0 && !hasSysResource() {
+		return fmt.Errorf("pressure window %s must be a multiple of %s without "+
+			"CAP_SYS_RESOURCE", t.Window, UnprivilegedPressureWindow)
+	}
+	if t.Stall <= 0 || t.Stall > t.Window {
+		return fmt.Errorf("pressure stall %s out of range (0, %s]", t.Stall, t.Window)
+	}
+
+	return nil
+}
+
+// Checks if this process has CAP_SYS_RESOURCE in its effective set
+func hasSysResource() bool {
+	status, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if value, found := strings.CutPrefix(line, "CapEff:"); found {
+			caps, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+			return err == nil && caps&(1<<unix.CAP_SYS_RESOURCE) != 0
+		}
+	}
+
+	return false
+}
+
+// PressureTrigger gets notified by the kernel when the stall time
+// of a control group crosses the threshold within the window. It
+// fires at most once per window while the pressure lasts.
+type PressureTrigger struct {
+	threshold PressureThreshold
+	file      *os.File
+	// Wakes up Wait once closed
+	closeFD int
+	// Files are released by Wait if closed while waiting
+	lock    sync.Mutex
+	waiting bool
+	closed  bool
+}
+
+// Returns pressure of the resource in the control group
+func (m *ControlGroupsManager) GetPressure(resource PressureResource) (Pressure, error) {
+	return readPressure(m.fs, filepath.Join(m.cgroupPath, string(resource)+".pressure"))
+}
+
+// Registers a pressure trigger on the control group. The trigger
+// must be closed once done.
+func (m *ControlGroupsManager) NewPressureTrigger(
+	threshold PressureThreshold) (*PressureTrigger, error) {
+	if err := threshold.Validate(); err != nil {
+		return nil, err
+	}
+	filePath := filepath.Join(m.cgroupPath, string(threshold.Resource)+".pressure")
+	// Trigger lives as long as the file stays open
+	file, err := m.fs.OpenFile(filePath, os.O_RDWR|unix.O_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	kind := "some"
+	if threshold.Full {
+		kind = "full"
+	}
+	value := fmt.Sprintf("%s %d %d", kind, threshold.Stall.Microseconds(),
+		threshold.Window.Microseconds())
+	if _, err := file.Write([]byte(value)); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed writing %q to %s: %w", value, filePath, err)
+	}
+	closeFD, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed creating eventfd: %w", err)
+	}
+
+	return &PressureTrigger{threshold: threshold, file: file, closeFD: closeFD}, nil
+}
+
+func (t *PressureTrigger) GetThreshold() PressureThreshold {
+	return t.threshold
+}
+
+// Blocks till the trigger fires, returning true, or till the trigger
+// is closed, returning false. Must not be called concurrently.
+func (t *PressureTrigger) Wait() (bool, error) {
+	t.lock.Lock()
+	if t.closed {
+		t.lock.Unlock()
+		return false, nil
+	}
+	t.waiting = true
+	t.lock.Unlock()
+	defer func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		t.waiting = false
+		if t.closed {
+			t.release()
+		}
+	}()
+	fds := []unix.PollFd{
+		{Fd: int32(t.file.Fd()), Events: unix.POLLPRI},
+		{Fd: int32(t.closeFD), Events: unix.POLLIN},
+	}
+	for {
+		if _, err := unix.Poll(fds, -1); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return false, fmt.Errorf("failed polling %s: %w", t.file.Name(), err)
+		}
+		if fds[1].Revents != 0 {
+			return false, nil
+		}
+		if fds[0].Revents&unix.POLLERR != 0 {
+			// Control group is gone
+			return false, fmt.Errorf("pressure trigger on %s failed", t.file.Name())
+		}
+		if fds[0].Revents&unix.POLLPRI != 0 {
+			return true, nil
+		}
+	}
+}
+
+// Unregisters the trigger, waking up Wait if blocked. Safe to be
+// called more than once.
+func (t *PressureTrigger) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	if t.waiting {
+		_, err := unix.Write(t.closeFD, []byte{1, 0, 0, 0, 0, 0, 0, 0})
+		return err
+	}
+
+	return t.release()
+}
+
+// Registration is dropped along with the file
+func (t *PressureTrigger) release() error {
+	unix.Close(t.closeFD)
+
+	return t.file.Close()
+}
+
+// Reads a pressure file with lines such as
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0". Missing file,
+// as in case of a kernel without PSI, reads as zero.
+func readPressure(fs FS, filePath string) (Pressure, error) {
+	var pressure Pressure
+	content, err := fs.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return pressure, nil
+	}
+	if err != nil {
+		return pressure, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var data *PressureData
+		switch fields[0] {
+		case "some":
+			data = &pressure.Some
+		case "full":
+			data = &pressure.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			if key == "total" {
+				if data.TotalUsec, err = strconv.ParseUint(value, 10, 64); err != nil {
+					return pressure, fmt.Errorf("failed parsing %s: %w", filePath, err)
+				}
+				continue
+			}
+			avg, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return pressure, fmt.Errorf("failed parsing %s: %w", filePath, err)
+			}
+			switch key {
+			case "avg10":
+				data.Avg10 = avg
+			case "avg60":
+				data.Avg60 = avg
+			case "avg300":
+				data.Avg300 = avg
+			}
+		}
+	}
+
+	return pressure, nil
+}
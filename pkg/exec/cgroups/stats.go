@@ -0,0 +1,193 @@
+package cgroups
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Resource usage of a control group. Values of controllers
+// not enabled for the group are left zero.
+type Stats struct {
+	// From cpu.stat, in microseconds
+	CPUUsageUsec  uint64
+	CPUUserUsec   uint64
+	CPUSystemUsec uint64
+	// Throttling counters, present only with cpu controller
+	CPUPeriods          uint64
+	CPUThrottledPeriods uint64
+	CPUThrottledUsec    uint64
+	// From memory.current and memory.peak, in bytes
+	MemoryCurrent uint64
+	MemoryPeak    uint64
+	// Breakdown of memory.stat such as anon, file and kernel
+	MemoryStat map[string]uint64
+	// Counts of memory.max reached, OOM and OOM kills from memory.events
+	MemoryMaxEvents     uint64
+	MemoryOOMEvents     uint64
+	MemoryOOMKillEvents uint64
+	// Per device counters from io.stat
+	IO []IOStats
+	// From pids.current
+	PidsCurrent uint64
+	// Times a fork failed for reaching pids.max, from pids.events
+	PidsMaxEvents uint64
+	// Pressure stall information, available regardless of controllers
+	CPUPressure    Pressure
+	MemoryPressure Pressure
+	IOPressure     Pressure
+}
+
+type IOStats struct {
+	DeviceMajorNum int32
+	DeviceMinorNum int32
+	ReadBytes      uint64
+	WriteBytes     uint64
+	ReadIOs        uint64
+	WriteIOs       uint64
+}
+
+// Reads resource usage of the control group
+func (m *ControlGroupsManager) GetStats() (*Stats, error) {
+	stats := &Stats{}
+	cpuStat, err := readUintKeyValues(m.fs, filepath.Join(m.cgroupPath, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	stats.CPUUsageUsec = cpuStat["usage_usec"]
+	stats.CPUUserUsec = cpuStat["user_usec"]
+	stats.CPUSystemUsec = cpuStat["system_usec"]
+	stats.CPUPeriods = cpuStat["nr_periods"]
+	stats.CPUThrottledPeriods = cpuStat["nr_throttled"]
+	stats.CPUThrottledUsec = cpuStat["throttled_usec"]
+	for _, v := range []struct {
+		name   string
+		target *uint64
+	}{
+		{"memory.current", &stats.MemoryCurrent},
+		{"memory.peak", &stats.MemoryPeak},
+		{"pids.current", &stats.PidsCurrent},
+	} {
+		if *v.target, err = readUint(m.fs, filepath.Join(m.cgroupPath, v.name)); err != nil {
+			return nil, err
+		}
+	}
+	if stats.MemoryStat, err = readUintKeyValues(m.fs,
+		filepath.Join(m.cgroupPath, "memory.stat")); err != nil {
+		return nil, err
+	}
+	memoryEvents, err := readUintKeyValues(m.fs, filepath.Join(m.cgroupPath, "memory.events"))
+	if err != nil {
+		return nil, err
+	}
+	stats.MemoryMaxEvents = memoryEvents["max"]
+	stats.MemoryOOMEvents = memoryEvents["oom"]
+	stats.MemoryOOMKillEvents = memoryEvents["oom_kill"]
+	pidsEvents, err := readUintKeyValues(m.fs, filepath.Join(m.cgroupPath, "pids.events"))
+	if err != nil {
+		return nil, err
+	}
+	stats.PidsMaxEvents = pidsEvents["max"]
+	if stats.IO, err = readIOStats(m.fs, filepath.Join(m.cgroupPath, "io.stat")); err != nil {
+		return nil, err
+	}
+	for _, v := range []struct {
+		resource PressureResource
+		target   *Pressure
+	}{
+		{PressureCPU, &stats.CPUPressure},
+		{PressureMemory, &stats.MemoryPressure},
+		{PressureIO, &stats.IOPressure},
+	} {
+		if *v.target, err = m.GetPressure(v.resource); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// Reads a single value file. Missing file, as in case of a
+// controller not enabled, reads as zero.
+func readUint(fs FS, filePath string) (uint64, error) {
+	content, err := fs.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed parsing %s: %w", filePath, err)
+	}
+
+	return value, nil
+}
+
+// Reads a flat keyed file of numeric values. Missing file reads
+// as empty and values that are not numbers are skipped.
+func readUintKeyValues(fs FS, filePath string) (map[string]uint64, error) {
+	keyValues, err := readKeyValues(fs, filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]uint64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]uint64, len(keyValues))
+	for key, value := range keyValues {
+		if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+			ret[key] = v
+		}
+	}
+
+	return ret, nil
+}
+
+// Reads nested keyed io.stat with lines such as
+// "8:0 rbytes=1024 wbytes=0 rios=2 wios=0 dbytes=0 dios=0"
+func readIOStats(fs FS, filePath string) ([]IOStats, error) {
+	content, err := fs.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret := []IOStats{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var stats IOStats
+		if _, err := fmt.Sscanf(fields[0], "%d:%d",
+			&stats.DeviceMajorNum, &stats.DeviceMinorNum); err != nil {
+			return nil, fmt.Errorf("failed parsing %s: %w", filePath, err)
+		}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				stats.ReadBytes = v
+			case "wbytes":
+				stats.WriteBytes = v
+			case "rios":
+				stats.ReadIOs = v
+			case "wios":
+				stats.WriteIOs = v
+			}
+		}
+		ret = append(ret, stats)
+	}
+
+	return ret, nil
+}
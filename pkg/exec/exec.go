@@ -6,12 +6,16 @@ package exec
 import (
 	"context"
 	"fmt"
+	"net"
+	"os"
 	"os/exec"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/troplet/pkg/exec/cgroups"
 	"github.com/troplet/pkg/exec/mountfs"
+	"github.com/troplet/pkg/exec/netns"
 )
 
 type cmdStateType string
@@ -23,10 +27,39 @@ type Command struct {
 	args       []string
 	stdoutChan ReadChannel
 	stderrChan ReadChannel
+	stdinChan  WriteChannel
 	cgroupsMgr *cgroups.ControlGroupsManager
-	mountFSMgr *mountfs.MountFSManager
-	useNetNS   bool
-	usePIDNS   bool
+	// Parent of the control group and limits to be added to it
+	cgroupParent *cgroups.ParentGroup
+	cgroupLimits []func(*cgroups.ControlGroupsManager)
+	// Filesystem of the control group if created without parent
+	cgroupFS    cgroups.FS
+	newRootBase string
+	mounter     mountfs.Mounter
+	mountFSMgr  *mountfs.MountFSManager
+	useNetNS    bool
+	usePIDNS    bool
+	// Network the network namespace gets connected to, if any
+	network *netns.Network
+	netMode netns.Mode
+	egress  []netns.EgressRule
+	netNS   *netns.Namespace
+	// Run as a plain process without namespaces, new root and
+	// control group
+	unisolated bool
+	// User and group of the plain process, the ones of this
+	// process if nil
+	credential *syscall.Credential
+	usePTY     bool
+	ptyRows    uint16
+	ptyCols    uint16
+	stopPolicy StopPolicy
+	timeout    time.Duration
+	// Pressure thresholds watched while running and the callback
+	// called each time one is crossed
+	pressureThresholds []cgroups.PressureThreshold
+	pressureNotify     func(cgroups.PressureThreshold)
+	pressureTriggers   []*cgroups.PressureTrigger
 
 	// Internal state variables
 	id  string
@@ -39,14 +72,72 @@ type Command struct {
 	// comparison and transition, setting of exitError and exitCode
 	lock     sync.RWMutex
 	cmdState cmdStateType
+	// Serializes pause, resume and stop. Freezing and thawing the
+	// control group waits without holding the lock above.
+	freezeLock sync.Mutex
 	// Process group id. Applicable only after the process has started
 	// successfully
 	pgid int
+	// Master end of the pty, set only if running with pty
+	ptyMaster *os.File
+	// How the command got stopped, if it did
+	stopOutcome StopOutcome
+	// Set if the command got stopped for exceeding its timeout
+	timedOut bool
+	// Why the command terminated, set once terminated
+	terminationReason TerminationReason
+	// Closed once the command has terminated after running
+	done chan struct{}
 }
 
 // Channel type to send stdout or stderror data to application
 type ReadChannel chan []byte
 
+// Channel type to receive stdin data from application.
+// Closing the channel closes stdin of the command.
+type WriteChannel chan []byte
+
+// How the command gets stopped on Stop or on context cancellation.
+// The signal is sent first, and if the command is still running after
+// the grace period, all of its processes are killed via the control
+// group. Zero grace period kills right away.
+type StopPolicy struct {
+	Signal      syscall.Signal
+	GracePeriod time.Duration
+}
+
+// Outcome of stopping the command
+type StopOutcome string
+
+const (
+	// Command was not stopped
+	StopOutcomeNone StopOutcome = ""
+	// Command exited within the grace period after the stop signal
+	StopOutcomeGraceful StopOutcome = "graceful"
+	// Command was killed after the grace period
+	StopOutcomeKilled StopOutcome = "killed"
+)
+
+// Reason of termination of the command
+type TerminationReason string
+
+const (
+	// Command has not terminated
+	TerminationReasonNone TerminationReason = ""
+	// Command exited on its own
+	TerminationReasonExited TerminationReason = "exited"
+	// Command got killed by a signal it did not handle
+	TerminationReasonSignaled TerminationReason = "signaled"
+	// Command got killed by the OOM killer for exceeding memory limit
+	TerminationReasonOOMKilled TerminationReason = "oom-killed"
+	// Command got stopped for exceeding its timeout
+	TerminationReasonTimedOut TerminationReason = "timed-out"
+	// Command got stopped by Stop or context cancellation
+	TerminationReasonStopped TerminationReason = "stopped"
+	// Command could not be started
+	TerminationReasonFailedToStart TerminationReason = "failed-to-start"
+)
+
 // Command options to construct the command
 type CommandOption func(*Command)
 
@@ -64,6 +155,15 @@ func WithStderrChan(stderrChan ReadChannel) CommandOption {
 	}
 }
 
+// Option to register stdin channel. Without it stdin of the
+// command is empty. The application must close the channel once
+// done, data sent after the command exits is discarded.
+func WithStdinChan(stdinChan WriteChannel) CommandOption {
+	return func(c *Command) {
+		c.stdinChan = stdinChan
+	}
+}
+
 // Option to set CPU cgroups limit
 func WithCPULimit(quotaMillSeconds, periodMillSeconds int64) CommandOption {
 	return func(c *Command) {
@@ -71,17 +171,87 @@ func WithCPULimit(quotaMillSeconds, periodMillSeconds int64) CommandOption {
 	}
 }
 
+// Option to set CPU weight relative to the other commands
+// under the same parent control group
+func WithCPUWeight(weight int64) CommandOption {
+	return func(c *Command) {
+		c.setCPUWeight(weight)
+	}
+}
+
+// Option to pin the command to given CPUs and memory nodes, lists
+// such as "0-3,6". These must be within the effective ones of the
+// parent control group.
+func WithCPUSet(cpus, mems string) CommandOption {
+	return func(c *Command) {
+		c.setCPUSet(cpus, mems)
+	}
+}
+
 // Option to set memory cgroups limit
 func WithMemoryLimit(memKB int64) CommandOption {
 	return func(c *Command) {
-		c.setMemoryLimit(memKB)
+		c.setMemorySpec(cgroups.MemorySpec{MaxKB: memKB})
+	}
+}
+
+// Option to set memory cgroups controls, including soft limit,
+// protection from reclaim, swap limit and OOM kill of the group
+func WithMemorySpec(spec cgroups.MemorySpec) CommandOption {
+	return func(c *Command) {
+		c.setMemorySpec(spec)
+	}
+}
+
+// Option to set limit on number of processes via cgroups
+func WithPIDsLimit(maxPIDs int64) CommandOption {
+	return func(c *Command) {
+		c.setPIDsLimit(maxPIDs)
 	}
 }
 
 // Option to set IO cgroups limit
 func WithIOLimits(deviceMajorNum, deviceMinorNum int32, rbps, wbps int64) CommandOption {
 	return func(c *Command) {
-		c.setIOLimits(deviceMajorNum, deviceMinorNum, rbps, wbps)
+		c.setIOSpec(cgroups.IOSpec{Devices: []cgroups.IODeviceLimit{{
+			DeviceMajorNum: deviceMajorNum, DeviceMinorNum: deviceMinorNum,
+			ReadBps: rbps, WriteBps: wbps}}})
+	}
+}
+
+// Option to set IO cgroups limits and weights of multiple devices
+func WithIOSpec(spec cgroups.IOSpec) CommandOption {
+	return func(c *Command) {
+		c.setIOSpec(spec)
+	}
+}
+
+// Option to get notified when pressure stall of the command crosses
+// any of the thresholds. The notification is called from a separate
+// goroutine, at most once per window of the threshold while the
+// pressure lasts.
+func WithPressureAlerts(thresholds []cgroups.PressureThreshold,
+	notify func(cgroups.PressureThreshold)) CommandOption {
+	return func(c *Command) {
+		c.pressureThresholds = thresholds
+		c.pressureNotify = notify
+	}
+}
+
+// Option to create the control group of the command under the
+// parent group instead of cgroup2 mount root
+func WithControlGroupParent(parent *cgroups.ParentGroup) CommandOption {
+	return func(c *Command) {
+		c.cgroupParent = parent
+	}
+}
+
+// Option to manage the control group of the command on given
+// cgroup2 filesystem, such as a fake one for unprivileged tests.
+// With a parent group, the filesystem of the parent is used instead.
+func WithControlGroupFS(fs cgroups.FS) CommandOption {
+	return func(c *Command) {
+		c.cgroupFS = fs
 	}
 }
 
@@ -89,17 +259,46 @@ func WithIOLimits(deviceMajorNum, deviceMinorNum int32, rbps, wbps int64) Comman
 // with name "id" is created under this base.
 func WithNewRootBase(newRootBase string) CommandOption {
 	return func(c *Command) {
-		c.setNewRootBase(newRootBase)
+		c.newRootBase = newRootBase
 	}
 }
 
-// Options to isolate network
+// Option to prepare the new root with given mounter instead of
+// mounting for real, such as a fake one for unprivileged tests
+func WithMounter(mounter mountfs.Mounter) CommandOption {
+	return func(c *Command) {
+		c.mounter = mounter
+	}
+}
+
+// Options to isolate network. The command has only loopback.
 func WithUseNetNS() CommandOption {
 	return func(c *Command) {
 		c.useNetNS = true
 	}
 }
 
+// Option to isolate network and connect the network namespace
+// to given network. The command gets an address of the network
+// on its eth0 interface.
+func WithNetwork(network *netns.Network) CommandOption {
+	return func(c *Command) {
+		c.useNetNS = true
+		c.network = network
+	}
+}
+
+// Option to isolate network in given mode. Connected modes need
+// a network set with WithNetwork. If there are egress rules, the
+// command can send only to the destinations these allow. The default
+// mode is bridged with a network, loopback otherwise.
+func WithNetworkMode(mode netns.Mode, egress []netns.EgressRule) CommandOption {
+	return func(c *Command) {
+		c.useNetNS = true
+		c.netMode, c.egress = mode, egress
+	}
+}
+
 // Options to isolate PID
 func WithUsePIDNS() CommandOption {
 	return func(c *Command) {
@@ -107,6 +306,33 @@ func WithUsePIDNS() CommandOption {
 	}
 }
 
+// Option to set stop policy. The default policy kills right away.
+func WithStopPolicy(policy StopPolicy) CommandOption {
+	return func(c *Command) {
+		c.stopPolicy = policy
+	}
+}
+
+// Option to limit wall-clock time of the command. The command is
+// stopped as per its stop policy once the timeout expires.
+func WithTimeout(timeout time.Duration) CommandOption {
+	return func(c *Command) {
+		c.timeout = timeout
+	}
+}
+
+// Option to run the command on a pseudo-terminal of given window
+// size. The command becomes a session leader with the terminal as
+// its controlling terminal. Its output, including stderr, is sent
+// on the stdout channel and the stderr channel gets closed. Input
+// is taken from the stdin channel if set.
+func WithPTY(rows, cols uint16) CommandOption {
+	return func(c *Command) {
+		c.usePTY = true
+		c.ptyRows, c.ptyCols = rows, cols
+	}
+}
+
 // Returns new command with given name, args and options.
 // The name is mandatory argument.
 func NewCommand(name string, args []string, options ...CommandOption) (*Command, error) {
@@ -120,13 +346,26 @@ func (c *Command) GetID() string {
 	return c.id
 }
 
+// Returns address of the command in its network, nil if not
+// connected to a network
+func (c *Command) GetIP() net.IP {
+	if c.netNS == nil {
+		return nil
+	}
+
+	return c.netNS.GetIP()
+}
+
 // String representation of this command.
 func (c *Command) String() string {
 	return fmt.Sprintf("%s, %s %v", c.id, c.name, c.args)
 }
 
 // Executes this command. This call blocks till the
-// command has terminated.
+// command has terminated. The command is stopped as per
+// its stop policy once the context is done or its timeout
+// expires. If the command fails to start, it is moved to
+// terminated state with the failure as its exit error.
 func (c *Command) Execute(ctx context.Context) error {
 	return c.execute(ctx)
 }
@@ -163,23 +402,95 @@ func (c *Command) GetExitCode() (int, error) {
 	return c.exitCode, nil
 }
 
+// Checks if the command is paused
+func (c *Command) IsPaused() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.cmdState == cmdStatePaused
+}
+
+// Freezes all the processes of the running command via its
+// control group. Blocks till these are frozen.
+func (c *Command) Pause() error {
+	return c.pause()
+}
+
+// Thaws all the processes of the paused command. Blocks till
+// these are thawed.
+func (c *Command) Resume() error {
+	return c.resume()
+}
+
+// Returns resource usage of the command read from its control
+// group. Final totals remain available after the command has
+// terminated till Finish is called.
+func (c *Command) Stats() (*cgroups.Stats, error) {
+	return c.stats()
+}
+
+// Changes window size of the pty of running command
+func (c *Command) Resize(rows, cols uint16) error {
+	return c.resize(rows, cols)
+}
+
 // Tries terminating the command gracefully by sending
 // SIGTERM signal if running. Will return error in case
 // the command is not running or kill fails.
 func (c *Command) SendTermSignal() error {
+	return c.Signal(syscall.SIGTERM)
+}
+
+// Sends the signal to process group of the command if running.
+// Will return error in case the command is not running or
+// sending fails.
+func (c *Command) Signal(sig syscall.Signal) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	return c.sendSignalToGroup(syscall.SIGTERM)
+	return c.sendSignalToGroup(sig)
+}
+
+// Stops the running command as per its stop policy but with the
+// given grace period. This call blocks till the command terminates
+// or gets killed once the grace period expires.
+func (c *Command) Stop(gracePeriod time.Duration) error {
+	return c.stop(gracePeriod)
+}
+
+// Checks if the command got stopped for exceeding its timeout
+func (c *Command) IsTimedOut() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.timedOut
+}
+
+// Returns why the command terminated
+func (c *Command) GetTerminationReason() TerminationReason {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.terminationReason
+}
+
+// Returns how the command got stopped
+func (c *Command) GetStopOutcome() StopOutcome {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.stopOutcome
 }
 
-// Terminates the command forcefully by sending
-// SIGKILL signal if running. Will return error in case
-// the command is not running.
+// Terminates the command forcefully by killing all of its
+// processes via its control group, including the ones that left
+// its process group. Will return error in case the command is
+// not running.
 func (c *Command) Kill() error {
 	return c.kill()
 }
 
-// Performs cleanup of terminated command.
+// Performs cleanup of terminated command. Processes left behind
+// by the command are killed. Returns error if the cleanup fails.
 // Must be called after the command has terminated.
 func (c *Command) Finish() error {
 	return c.finish()
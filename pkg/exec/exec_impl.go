@@ -4,15 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/troplet/pkg/exec/cgroups"
 	"github.com/troplet/pkg/exec/mountfs"
+	"github.com/troplet/pkg/exec/netns"
 )
 
 // These are internal library states and may not directly
@@ -20,47 +23,112 @@ import (
 const (
 	cmdStateInit       cmdStateType = "init"
 	cmdStateRunning    cmdStateType = "running"
+	cmdStatePaused     cmdStateType = "paused"
 	cmdStateTerminated cmdStateType = "terminated"
 	cmdStateFinished   cmdStateType = "finished"
 )
 
-func newCommand(name string, args []string, options ...CommandOption) (execCmd *Command, err error) {
+func newCommand(name string, args []string, options ...CommandOption) (*Command, error) {
 	// Every command is assigned a unique id
 	id := uuid.NewString()
 
 	// Initialize defaults and mandatory params
-	execCmd = &Command{id: id, name: name, args: args,
-		cmdState: cmdStateInit}
+	execCmd := &Command{id: id, name: name, args: args,
+		cmdState: cmdStateInit, done: make(chan struct{}),
+		stopPolicy: StopPolicy{Signal: syscall.SIGKILL}}
 
-	// Cleanup of incomplete initialization
-	defer func() {
-		if err != nil && execCmd != nil {
-			execCmd.Finish()
+	// Read passed options
+	for _, option := range options {
+		option(execCmd)
+	}
+	if execCmd.unisolated {
+		execCmd.useNetNS, execCmd.usePIDNS = false, false
+		execCmd.network, execCmd.netMode, execCmd.egress = nil, "", nil
+		execCmd.newRootBase = ""
+		execCmd.pressureThresholds = nil
+		return execCmd, nil
+	}
+	if err := execCmd.initIsolation(); err != nil {
+		// Cleanup of incomplete initialization
+		if finishErr := execCmd.Finish(); finishErr != nil {
+			return nil, fmt.Errorf("%w, cleanup failed: %v", err, finishErr)
 		}
-	}()
-
-	execCmd.cgroupsMgr, err = cgroups.NewControlGroupsManager(id)
-	if err != nil {
 		return nil, err
 	}
 
-	// Read passed options
-	for _, option := range options {
-		option(execCmd)
+	return execCmd, nil
+}
+
+// Creates control group, root filesystem and network namespace of
+// the command. Whatever got created is left for Finish on error.
+func (c *Command) initIsolation() error {
+	var cgroupOpts []cgroups.Option
+	if c.cgroupFS != nil {
+		cgroupOpts = append(cgroupOpts, cgroups.WithFS(c.cgroupFS))
+	}
+	cgroupsMgr, err := cgroups.NewControlGroupsManager(c.id, c.cgroupParent, cgroupOpts...)
+	if err != nil {
+		return err
+	}
+	c.cgroupsMgr = cgroupsMgr
+	// Limits are added once the parent of the control group is known
+	for _, addLimit := range c.cgroupLimits {
+		addLimit(c.cgroupsMgr)
 	}
 	// Set cgroup values
-	if err = execCmd.cgroupsMgr.Set(); err != nil {
-		return nil, err
+	if err := c.cgroupsMgr.Set(); err != nil {
+		return err
+	}
+	// Triggers need the control group to exist
+	for _, threshold := range c.pressureThresholds {
+		trigger, err := c.cgroupsMgr.NewPressureTrigger(threshold)
+		if err != nil {
+			return err
+		}
+		c.pressureTriggers = append(c.pressureTriggers, trigger)
 	}
 
 	// Prepare filesystem under new root.  Assigned by options.
-	if execCmd.mountFSMgr != nil {
-		if err = execCmd.mountFSMgr.Mount(); err != nil {
-			return nil, err
+	if c.newRootBase != "" {
+		c.setNewRootBase(c.newRootBase)
+		if err := c.mountFSMgr.Mount(); err != nil {
+			return err
 		}
 	}
 
-	return execCmd, nil
+	// Network namespace is set up before the command starts in it,
+	// so that its network is ready by then
+	if c.useNetNS {
+		netNS, err := c.newNetNS()
+		if err != nil {
+			return err
+		}
+		c.netNS = netNS
+	}
+
+	return nil
+}
+
+// Creates network namespace of the command as per its network mode
+func (c *Command) newNetNS() (*netns.Namespace, error) {
+	mode := c.netMode
+	if mode == "" {
+		mode = netns.ModeLoopback
+		if c.network != nil {
+			mode = netns.ModeBridged
+		}
+	}
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+	if !mode.IsConnected() {
+		return netns.NewNamespace(mode == netns.ModeLoopback)
+	}
+	if c.network == nil {
+		return nil, fmt.Errorf("network mode %s needs a network", mode)
+	}
+
+	return c.network.Attach(c.id, mode, c.egress)
 }
 
 func (c *Command) finish() error {
@@ -78,33 +146,71 @@ func (c *Command) finish() error {
 		return fmt.Errorf("invalid command state")
 	}
 
+	// Triggers of a command that never ran are not closed yet
+	for _, trigger := range c.pressureTriggers {
+		trigger.Close()
+	}
+	var err error
 	if c.cgroupsMgr != nil {
-		c.cgroupsMgr.Finish()
+		err = c.cgroupsMgr.Finish()
 	}
 	if c.mountFSMgr != nil {
 		c.mountFSMgr.Finish()
 	}
+	if c.netNS != nil {
+		if netErr := c.netNS.Close(); err == nil {
+			err = netErr
+		}
+	}
 
-	return nil
+	return err
 }
 
 func (c *Command) setCPULimit(quotaMillSeconds, periodMillSeconds int64) {
-	c.cgroupsMgr.NewCPUControlGroup(quotaMillSeconds, periodMillSeconds)
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewCPUControlGroup(quotaMillSeconds, periodMillSeconds)
+	})
+}
+
+func (c *Command) setCPUWeight(weight int64) {
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewCPUWeightControlGroup(weight)
+	})
+}
+
+func (c *Command) setCPUSet(cpus, mems string) {
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewCPUSetControlGroup(cpus, mems)
+	})
+}
+
+func (c *Command) setMemorySpec(spec cgroups.MemorySpec) {
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewMemoryControlGroup(spec)
+	})
 }
 
-func (c *Command) setMemoryLimit(memKB int64) {
-	c.cgroupsMgr.NewMemoryControlGroup(memKB)
+func (c *Command) setPIDsLimit(maxPIDs int64) {
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewPIDsControlGroup(maxPIDs)
+	})
 }
 
 func (c *Command) setNewRootBase(newRootBase string) {
 	// The new root for each process will be created under the passed root base
 	// concatenated with a unique command ID, ensuring that multiple
 	// commands do not share the same root.
-	c.mountFSMgr = mountfs.NewMountFSManager(filepath.Join(newRootBase, c.id))
+	var mountOpts []mountfs.Option
+	if c.mounter != nil {
+		mountOpts = append(mountOpts, mountfs.WithMounter(c.mounter))
+	}
+	c.mountFSMgr = mountfs.NewMountFSManager(filepath.Join(newRootBase, c.id), mountOpts...)
 }
 
-func (c *Command) setIOLimits(deviceMajorNum, deviceMinorNum int32, rbps, wbps int64) {
-	c.cgroupsMgr.NewIOControlGroup(deviceMajorNum, deviceMinorNum, rbps, wbps)
+func (c *Command) setIOSpec(spec cgroups.IOSpec) {
+	c.cgroupLimits = append(c.cgroupLimits, func(m *cgroups.ControlGroupsManager) {
+		m.NewIOControlGroup(spec)
+	})
 }
 
 func (c *Command) execute(ctx context.Context) error {
@@ -112,45 +218,115 @@ func (c *Command) execute(ctx context.Context) error {
 	defer wg.Wait()
 
 	// Move to running state
-	changeStateToRunning := func() error {
-		var err error
+	changeStateToRunning := func() (err error) {
 		c.lock.Lock()
 		defer c.lock.Unlock()
 		if c.cmdState != cmdStateInit {
 			return fmt.Errorf("invalid command state")
 		}
-		c.cmd = exec.CommandContext(ctx, c.name, c.args...)
-		if c.stdoutChan != nil {
-			stdoutPipe, err := c.cmd.StdoutPipe()
+		defer func() {
+			if err != nil {
+				c.exitError, c.exitCode = err, -1
+				c.terminationReason = TerminationReasonFailedToStart
+				c.cmdState = cmdStateTerminated
+			}
+		}()
+		// Context is handled here as per stop policy
+		c.cmd = exec.Command(c.name, c.args...)
+		// Pipes are created here instead of using StdoutPipe and
+		// StderrPipe since Wait closes those before they are fully
+		// read. The ends handed to the command are closed in this
+		// process once the command has started, so that the readers
+		// see EOF only after the command and its children exit.
+		var childFiles []*os.File
+		defer func() {
+			for _, file := range childFiles {
+				file.Close()
+			}
+		}()
+		createPipe := func(dst ReadChannel) (*os.File, error) {
+			reader, writer, err := os.Pipe()
 			if err != nil {
-				return fmt.Errorf("failed creating stdout pipe: %w", err)
+				return nil, err
 			}
+			childFiles = append(childFiles, writer)
 			wg.Add(1)
 			go func() {
-				c.readPipe(c.stdoutChan, stdoutPipe)
+				c.readPipe(dst, reader)
 				wg.Done()
 			}()
+
+			return writer, nil
 		}
-		if c.stderrChan != nil {
-			stderrPipe, err := c.cmd.StderrPipe()
+		if c.usePTY {
+			// Terminal is shared by stdin, stdout and stderr
+			// of the command and is read as stdout
+			if c.stdoutChan == nil {
+				return fmt.Errorf("pty requires stdout channel")
+			}
+			master, slave, err := openPTY(c.ptyRows, c.ptyCols)
 			if err != nil {
-				return fmt.Errorf("failed creating stderr pipe: %w", err)
+				return err
 			}
+			childFiles = append(childFiles, slave)
+			c.cmd.Stdin, c.cmd.Stdout, c.cmd.Stderr = slave, slave, slave
+			if c.stdinChan != nil {
+				// Separate file so that the reader and the
+				// writer can close theirs independently
+				masterFD, err := syscall.Dup(int(master.Fd()))
+				if err != nil {
+					master.Close()
+					return fmt.Errorf("failed to dup pty: %w", err)
+				}
+				syscall.CloseOnExec(masterFD)
+				go c.writePipe(os.NewFile(uintptr(masterFD), master.Name()), c.stdinChan)
+			}
+			c.ptyMaster = master
 			wg.Add(1)
 			go func() {
-				c.readPipe(c.stderrChan, stderrPipe)
+				c.readPipe(c.stdoutChan, master)
 				wg.Done()
 			}()
+			if c.stderrChan != nil {
+				close(c.stderrChan)
+			}
+		} else {
+			if c.stdoutChan != nil {
+				if c.cmd.Stdout, err = createPipe(c.stdoutChan); err != nil {
+					return fmt.Errorf("failed creating stdout pipe: %w", err)
+				}
+			}
+			if c.stderrChan != nil {
+				if c.cmd.Stderr, err = createPipe(c.stderrChan); err != nil {
+					return fmt.Errorf("failed creating stderr pipe: %w", err)
+				}
+			}
+			if c.stdinChan != nil {
+				reader, writer, err := os.Pipe()
+				if err != nil {
+					return fmt.Errorf("failed creating stdin pipe: %w", err)
+				}
+				childFiles = append(childFiles, reader)
+				c.cmd.Stdin = reader
+				// Not waited for since it runs till the application
+				// closes the channel
+				go c.writePipe(writer, c.stdinChan)
+			}
 		}
 		// TODO: Config candidate
 		c.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if c.usePTY {
+			// Session leader with the terminal as its controlling
+			// terminal, which also makes it a process group leader
+			c.cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true,
+				Setctty: true, Ctty: 0}
+		}
+		if c.credential != nil {
+			c.cmd.SysProcAttr.Credential = c.credential
+		}
 		if c.usePIDNS {
 			c.cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWPID
 		}
-		if c.useNetNS {
-			c.cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
-			c.cmd.SysProcAttr.Unshareflags |= syscall.CLONE_NEWNET
-		}
 		if c.mountFSMgr != nil {
 			c.cmd.SysProcAttr.Chroot = c.mountFSMgr.GetMountRoot()
 			c.cmd.Dir = "/"
@@ -158,8 +334,9 @@ func (c *Command) execute(ctx context.Context) error {
 			c.cmd.SysProcAttr.Unshareflags |= syscall.CLONE_NEWNS
 		}
 
-		// Pass control-groups directory FD to the process
-		if c.cgroupsMgr != nil {
+		// Pass control-groups directory FD to the process, unless
+		// it can only be added once started
+		if c.cgroupsMgr != nil && c.cgroupsMgr.CanCloneInto() {
 			c.cmd.SysProcAttr.CgroupFD, err = c.cgroupsMgr.GetControlGroupsFD()
 			if err != nil {
 				return err
@@ -168,10 +345,21 @@ func (c *Command) execute(ctx context.Context) error {
 			c.cmd.SysProcAttr.UseCgroupFD = true
 		}
 
-		// Execute command
-		if err := c.cmd.Start(); err != nil {
+		// Execute command, in its network namespace if any
+		start := c.cmd.Start
+		if c.netNS != nil {
+			start = func() error { return c.netNS.Do(c.cmd.Start) }
+		}
+		if err := start(); err != nil {
 			return fmt.Errorf("failed starting command: %w", err)
 		}
+		if c.cgroupsMgr != nil && !c.cgroupsMgr.CanCloneInto() {
+			if err := c.cgroupsMgr.AddProcess(c.cmd.Process.Pid); err != nil {
+				c.cmd.Process.Kill()
+				c.cmd.Wait()
+				return err
+			}
+		}
 		c.pgid, err = syscall.Getpgid(c.cmd.Process.Pid)
 		c.cmdState = cmdStateRunning
 
@@ -180,9 +368,29 @@ func (c *Command) execute(ctx context.Context) error {
 	if err := changeStateToRunning(); err != nil {
 		return err
 	}
+	c.watchPressure(&wg)
+	go func() {
+		var timeoutChan <-chan time.Time
+		if c.timeout > 0 {
+			timer := time.NewTimer(c.timeout)
+			defer timer.Stop()
+			timeoutChan = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			c.stop(c.stopPolicy.GracePeriod)
+		case <-timeoutChan:
+			c.lock.Lock()
+			c.timedOut = true
+			c.lock.Unlock()
+			c.stop(c.stopPolicy.GracePeriod)
+		case <-c.done:
+		}
+	}()
 
 	// Wait for the process to terminate
 	err := c.cmd.Wait()
+	defer close(c.done)
 	// Move to terminated state and collect exit code and error
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -190,6 +398,7 @@ func (c *Command) execute(ctx context.Context) error {
 	if c.cmd != nil && c.cmd.ProcessState != nil {
 		c.exitCode = c.cmd.ProcessState.ExitCode()
 	}
+	c.terminationReason = c.getTerminationReason()
 
 	c.cmdState = cmdStateTerminated
 
@@ -197,12 +406,64 @@ func (c *Command) execute(ctx context.Context) error {
 	return err
 }
 
-func (c *Command) readPipe(dst ReadChannel, src io.Reader) {
+// Notifies crossings of the pressure thresholds till the command
+// terminates
+func (c *Command) watchPressure(wg *sync.WaitGroup) {
+	for _, trigger := range c.pressureTriggers {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for {
+				fired, err := trigger.Wait()
+				if err != nil || !fired {
+					return
+				}
+				c.pressureNotify(trigger.GetThreshold())
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			<-c.done
+			trigger.Close()
+		}()
+	}
+}
+
+// Must be called with lock held once the command has terminated
+// after running
+func (c *Command) getTerminationReason() TerminationReason {
+	if c.timedOut {
+		return TerminationReasonTimedOut
+	}
+	if c.stopOutcome != StopOutcomeNone {
+		return TerminationReasonStopped
+	}
+	if c.cmd == nil || c.cmd.ProcessState == nil {
+		// Never waited on successfully
+		return TerminationReasonFailedToStart
+	}
+	status, ok := c.cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return TerminationReasonExited
+	}
+	if status.Signal() == syscall.SIGKILL && c.cgroupsMgr != nil {
+		if oomKills, err := c.cgroupsMgr.GetOOMKills(); err == nil && oomKills != 0 {
+			return TerminationReasonOOMKilled
+		}
+	}
+
+	return TerminationReasonSignaled
+}
+
+func (c *Command) readPipe(dst ReadChannel, src io.ReadCloser) {
+	defer src.Close()
 	for {
 		// TODO: Config candidate
 		// TODO: This has GC overhead
 		buf := make([]byte, 128)
-		n, err := io.ReadFull(src, buf)
+		// Pass whatever is available so that the output
+		// is not held back till the buffer fills up
+		n, err := src.Read(buf)
 		if n != 0 {
 			dst <- buf[:n]
 		}
@@ -213,18 +474,194 @@ func (c *Command) readPipe(dst ReadChannel, src io.Reader) {
 	}
 }
 
-func (c *Command) kill() error {
+func (c *Command) writePipe(dst io.WriteCloser, src WriteChannel) {
+	writable := true
+	for data := range src {
+		// Once the command has closed its stdin or exited,
+		// the rest is discarded
+		if writable {
+			_, err := dst.Write(data)
+			writable = err == nil
+		}
+	}
+	dst.Close()
+}
+
+func (c *Command) resize(rows, cols uint16) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.ptyMaster == nil {
+		return fmt.Errorf("command has no pty")
+	}
+	if c.cmdState != cmdStateRunning {
+		return fmt.Errorf("invalid command state to resize")
+	}
+
+	return setPTYSize(c.ptyMaster, rows, cols)
+}
+
+func (c *Command) pause() error {
+	c.freezeLock.Lock()
+	defer c.freezeLock.Unlock()
+	c.lock.Lock()
+	if c.cmdState != cmdStateRunning {
+		c.lock.Unlock()
+		return fmt.Errorf("invalid command state to pause")
+	}
+	if c.cgroupsMgr == nil {
+		defer c.lock.Unlock()
+		// Processes that left the process group keep running
+		if err := c.sendSignalToGroup(syscall.SIGSTOP); err != nil {
+			return err
+		}
+		c.cmdState = cmdStatePaused
+		return nil
+	}
+	cgroupsMgr := c.cgroupsMgr
+	c.lock.Unlock()
+	// Waiting for the group to freeze does not block others
+	if err := cgroupsMgr.Freeze(); err != nil {
+		// Do not leave it partially frozen
+		cgroupsMgr.Thaw()
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cmdState != cmdStateRunning {
+		// Terminated meanwhile
+		return fmt.Errorf("invalid command state to pause")
+	}
+	c.cmdState = cmdStatePaused
+
+	return nil
+}
+
+func (c *Command) resume() error {
+	c.freezeLock.Lock()
+	defer c.freezeLock.Unlock()
+
+	return c.thaw()
+}
+
+// Resumes paused command. Must be called with freeze lock held,
+// but not the lock.
+func (c *Command) thaw() error {
+	c.lock.Lock()
+	if c.cmdState != cmdStatePaused {
+		c.lock.Unlock()
+		return fmt.Errorf("invalid command state to resume")
+	}
+	if c.cgroupsMgr == nil {
+		defer c.lock.Unlock()
+		if err := c.sendSignalToGroup(syscall.SIGCONT); err != nil {
+			return err
+		}
+		c.cmdState = cmdStateRunning
+		return nil
+	}
+	cgroupsMgr := c.cgroupsMgr
+	c.lock.Unlock()
+	if err := cgroupsMgr.Thaw(); err != nil {
+		return err
+	}
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	if c.cmdState == cmdStatePaused {
+		c.cmdState = cmdStateRunning
+	}
+
+	return nil
+}
+
+func (c *Command) stats() (*cgroups.Stats, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cmdState != cmdStateRunning && c.cmdState != cmdStatePaused &&
+		c.cmdState != cmdStateTerminated {
+		return nil, fmt.Errorf("invalid command state to get stats")
+	}
+	if c.cgroupsMgr == nil {
+		return nil, ErrNoControlGroup
+	}
+
+	return c.cgroupsMgr.GetStats()
+}
+
+func (c *Command) stop(gracePeriod time.Duration) error {
+	// Not paused again till the lock is taken
+	c.freezeLock.Lock()
+	if c.IsPaused() {
+		// A frozen command cannot handle the stop signal
+		if err := c.thaw(); err != nil {
+			c.freezeLock.Unlock()
+			return err
+		}
+	}
+	c.lock.Lock()
+	c.freezeLock.Unlock()
 	if c.cmdState != cmdStateRunning {
+		c.lock.Unlock()
+		return fmt.Errorf("invalid command state to stop")
+	}
+	if gracePeriod > 0 {
+		err := c.sendSignalToGroup(c.stopPolicy.Signal)
+		if err == nil && c.stopOutcome == StopOutcomeNone {
+			// Stays so unless killed after the grace period
+			c.stopOutcome = StopOutcomeGraceful
+		}
+		c.lock.Unlock()
+		if err != nil {
+			return err
+		}
+		select {
+		case <-c.done:
+			return nil
+		case <-time.After(gracePeriod):
+		}
+		c.lock.Lock()
+		if c.cmdState != cmdStateRunning && c.cmdState != cmdStatePaused {
+			// Terminated meanwhile
+			c.lock.Unlock()
+			return nil
+		}
+	}
+	c.stopOutcome = StopOutcomeKilled
+	err := c.killAll()
+	c.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	<-c.done
+
+	return nil
+}
+
+func (c *Command) kill() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cmdState != cmdStateRunning && c.cmdState != cmdStatePaused {
 		return fmt.Errorf("invalid command state to send signal")
 	}
 
+	return c.killAll()
+}
+
+// Kills all the processes of the command including the ones
+// that left its process group. Falls back to killing the process
+// group if the control group cannot be killed. Frozen processes
+// get killed as well. Must be called with lock held.
+func (c *Command) killAll() error {
+	if c.cgroupsMgr != nil && c.cgroupsMgr.Kill() == nil {
+		return nil
+	}
+
 	return c.sendSignalToGroup(syscall.SIGKILL)
 }
 
+// Signals sent to a paused command are delivered once it resumes,
+// except SIGKILL
 func (c *Command) sendSignalToGroup(sig syscall.Signal) error {
-	if c.cmdState != cmdStateRunning ||
+	if (c.cmdState != cmdStateRunning && c.cmdState != cmdStatePaused) ||
 		c.cmd == nil || c.cmd.Process == nil {
 		return fmt.Errorf("invalid command state to send signal")
 	}
@@ -2,12 +2,25 @@ package exec
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/nftables"
+	"github.com/vishvananda/netlink"
+
+	"github.com/troplet/internal/cgroupstest"
+	"github.com/troplet/pkg/exec/cgroups"
+	"github.com/troplet/pkg/exec/netns"
 )
 
 type testJobReadData struct {
@@ -27,6 +40,7 @@ type testJobReadData struct {
 	expectStdoutStr  string
 	expectStderr     bool
 	expectStderrStr  string
+	expectReason     TerminationReason
 }
 
 func (d *testJobReadData) testStartRead() {
@@ -61,19 +75,60 @@ func (d *testJobReadData) testWait() {
 	d.wg.Wait()
 }
 
+// Returns command with its control group on a fake cgroup2
+// filesystem, so that it runs without privileges
+func newTestCommand(t *testing.T, name string, args []string,
+	options ...CommandOption) (*Command, error) {
+	fs, err := cgroupstest.NewFakeFS(t.TempDir(), cgroups.DefaultControllers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return NewCommand(name, args, append(options, WithControlGroupFS(fs))...)
+}
+
+// Skips the test unless privileged, as needed for namespaces,
+// mounts and the accounting of real control groups
+func requireRoot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Requires root")
+	}
+}
+
+// Skips the test unless the controller is enabled for the control
+// groups at the root of cgroup2 mount
+func requireController(t *testing.T, controller string) {
+	mounts, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(string(mounts), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != "cgroup2" {
+			continue
+		}
+		controls, err := os.ReadFile(filepath.Join(fields[1], "cgroup.subtree_control"))
+		if err == nil && slices.Contains(strings.Fields(string(controls)), controller) {
+			return
+		}
+	}
+	t.Skipf("Requires %s controller", controller)
+}
+
 func TestBasic(t *testing.T) {
 	createCommand := func(d *testJobReadData) (*Command, error) {
-		return NewCommand(d.command, d.args,
+		return newTestCommand(t, d.command, d.args,
 			WithStdoutChan(d.stdoutChan),
 			WithStderrChan(d.stderrChan))
 	}
 
 	testData := []*testJobReadData{
 		{
-			testName:    "Basic ls cmd",
-			command:     "ls",
-			args:        []string{"-lrt"},
-			expectError: false,
+			testName:     "Basic ls cmd",
+			command:      "ls",
+			args:         []string{"-lrt"},
+			expectError:  false,
+			expectReason: TerminationReasonExited,
 		},
 		{
 			testName:        "Find bash",
@@ -83,10 +138,11 @@ func TestBasic(t *testing.T) {
 			expectStdoutStr: "/usr/bin/bash\n",
 		},
 		{
-			testName:    "Unknown command",
-			command:     "FooBar123",
-			args:        []string{""},
-			expectError: true,
+			testName:     "Unknown command",
+			command:      "FooBar123",
+			args:         []string{""},
+			expectError:  true,
+			expectReason: TerminationReasonFailedToStart,
 		},
 		{
 			testName:         "Timeout command",
@@ -95,6 +151,7 @@ func TestBasic(t *testing.T) {
 			timeout:          2 * time.Second,
 			expectError:      true,
 			expectedErrorStr: "signal: killed",
+			expectReason:     TerminationReasonStopped,
 		},
 		{
 			testName:    "Wildcard ls with error",
@@ -102,6 +159,13 @@ func TestBasic(t *testing.T) {
 			args:        []string{"*"},
 			expectError: true,
 		},
+		{
+			testName:     "Killed by signal",
+			command:      "/usr/bin/bash",
+			args:         []string{"-c", "kill -KILL $$"},
+			expectError:  true,
+			expectReason: TerminationReasonSignaled,
+		},
 		{
 			testName: "Wildcard ls fixed",
 			command:  "/usr/bin/bash",
@@ -133,6 +197,11 @@ func TestBasic(t *testing.T) {
 				t.Errorf("Unexpected result: %s", diff)
 			}
 		}
+		if d.expectReason != TerminationReasonNone {
+			if diff := cmp.Diff(d.expectReason, cmd.GetTerminationReason()); diff != "" {
+				t.Errorf("Unexpected result: %s", diff)
+			}
+		}
 		if d.expectedErrorStr != "" {
 			exitErr, err := cmd.GetExitError()
 			if diff := cmp.Diff(nil, err); diff != "" {
@@ -152,7 +221,80 @@ func TestBasic(t *testing.T) {
 	}
 }
 
+func TestOutputChunks(t *testing.T) {
+	t.Logf("Executing test: Output passed as it is available")
+	stdoutChan, stderrChan := make(ReadChannel), make(ReadChannel)
+	cmd, err := newTestCommand(t, "/usr/bin/bash",
+		[]string{"-c", "printf a; sleep 0.2; head -c 300 /dev/zero | tr '\\0' x"},
+		WithStdoutChan(stdoutChan),
+		WithStderrChan(stderrChan))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cmd.Finish()
+	chunks := []string{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for data := range stdoutChan {
+			chunks = append(chunks, string(data))
+		}
+	}()
+	go func() {
+		for range stderrChan {
+		}
+	}()
+	cmd.Execute(context.Background())
+	<-done
+	// Not held back till the chunk fills up
+	if len(chunks) == 0 || chunks[0] != "a" {
+		t.Fatalf("Unexpected result: %q", chunks)
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 128 {
+			t.Errorf("Unexpected chunk size: %d", len(chunk))
+		}
+	}
+	if diff := cmp.Diff("a"+strings.Repeat("x", 300), strings.Join(chunks, "")); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestInitCleanup(t *testing.T) {
+	t.Logf("Executing test: Failing pressure trigger")
+	fs, err := cgroupstest.NewFakeFS(t.TempDir(), cgroups.DefaultControllers)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mountPath, err := fs.GetMountPath()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	before, err := os.ReadDir(mountPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The control group exists by the time the second trigger fails
+	cmd, err := NewCommand("/usr/bin/true", nil, WithControlGroupFS(fs),
+		WithPressureAlerts([]cgroups.PressureThreshold{
+			{Resource: cgroups.PressureCPU, Stall: time.Second, Window: 2 * time.Second},
+			{Resource: cgroups.PressureCPU, Stall: time.Second, Window: time.Minute},
+		}, nil))
+	if err == nil {
+		cmd.Finish()
+		t.Fatalf("Expected error creating pressure trigger")
+	}
+	after, err := os.ReadDir(mountPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(len(before), len(after)); diff != "" {
+		t.Errorf("Control group not removed: %s", diff)
+	}
+}
+
 func TestNewPIDNetNS(t *testing.T) {
+	requireRoot(t)
 	createCommand := func(d *testJobReadData) (*Command, error) {
 		return NewCommand(d.command, d.args,
 			WithStdoutChan(d.stdoutChan),
@@ -169,13 +311,12 @@ func TestNewPIDNetNS(t *testing.T) {
 			expectStdoutStr: "1\n",
 		},
 		{
-			// This will ideally fail if the network namespace
-			// is not isolated
-			testName:        "Add localhost loopback address on lo interface",
-			command:         "ip",
-			args:            []string{"addr", "add", "127.0.0.1/8", "dev", "lo"},
+			// Loopback is the only interface and is up
+			testName:        "Only loopback interface",
+			command:         "/usr/bin/bash",
+			args:            []string{"-c", "ip -o -4 addr show up | grep -o 'lo .*/8'"},
 			expectError:     false,
-			expectStdoutStr: "",
+			expectStdoutStr: "lo    inet 127.0.0.1/8\n",
 		},
 	}
 	for _, d := range testData {
@@ -222,7 +363,86 @@ func TestNewPIDNetNS(t *testing.T) {
 	}
 }
 
+func TestNetwork(t *testing.T) {
+	requireRoot(t)
+	network, err := netns.NewNetwork("trexectest0", "10.97.0.0/24", t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		if link, err := netlink.LinkByName(network.GetBridgeName()); err == nil {
+			netlink.LinkDel(link)
+		}
+		if c, err := nftables.New(); err == nil {
+			for _, family := range []nftables.TableFamily{nftables.TableFamilyINet,
+				nftables.TableFamilyBridge} {
+				c.DelTable(&nftables.Table{Family: family,
+					Name: "troplet_" + network.GetBridgeName()})
+			}
+			c.Flush()
+		}
+	}()
+	d := &testJobReadData{}
+	d.testStartRead()
+	cmd, err := NewCommand("/usr/bin/bash", []string{"-c",
+		"ip -o -4 addr show dev eth0 | grep -o '10.97.[0-9.]*/24'; " +
+			"ip route show default"},
+		WithStdoutChan(d.stdoutChan),
+		WithStderrChan(d.stderrChan),
+		WithNetwork(network))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("10.97.0.2", cmd.GetIP().String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Network is ready by the time the command starts
+	cmd.Execute(context.Background())
+	d.testWait()
+	if diff := cmp.Diff("10.97.0.2/24\ndefault via 10.97.0.1 dev eth0 \n",
+		d.stdoutStrBuilder.String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if err := cmd.Finish(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// Address is released on finish
+	cmd, err = NewCommand("true", nil, WithNetwork(network))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cmd.Finish()
+	if diff := cmp.Diff("10.97.0.2", cmd.GetIP().String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Connected modes need a network
+	if _, err := NewCommand("true", nil, WithNetworkMode(netns.ModeNAT, nil)); err == nil {
+		t.Errorf("Expected error for missing network")
+	}
+	// Not even loopback is up in none mode
+	d = &testJobReadData{}
+	d.testStartRead()
+	cmd, err = NewCommand("/usr/bin/bash", []string{"-c", "ip -o link show up | wc -l"},
+		WithStdoutChan(d.stdoutChan),
+		WithStderrChan(d.stderrChan),
+		WithNetwork(network),
+		WithNetworkMode(netns.ModeNone, nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cmd.Finish()
+	if cmd.GetIP() != nil {
+		t.Errorf("Expected no address, got %s", cmd.GetIP())
+	}
+	cmd.Execute(context.Background())
+	d.testWait()
+	if diff := cmp.Diff("0\n", d.stdoutStrBuilder.String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
 func TestNewRootCGroups(t *testing.T) {
+	requireRoot(t)
 	createCommand := func(d *testJobReadData) (*Command, error) {
 		return NewCommand(d.command, d.args,
 			WithStdoutChan(d.stdoutChan),
@@ -285,3 +505,355 @@ func TestNewRootCGroups(t *testing.T) {
 		}
 	}
 }
+
+func TestStdin(t *testing.T) {
+	d := &testJobReadData{testName: "Cat stdin", command: "cat",
+		expectStdoutStr: "hello\nworld\n"}
+	t.Logf("Executing test: %s", d.testName)
+	d.testStartRead()
+	stdinChan := make(WriteChannel)
+	cmd, err := newTestCommand(t, d.command, d.args,
+		WithStdoutChan(d.stdoutChan),
+		WithStderrChan(d.stderrChan),
+		WithStdinChan(stdinChan))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	go func() {
+		stdinChan <- []byte("hello\n")
+		stdinChan <- []byte("world\n")
+		// Closing stdin lets cat exit
+		close(stdinChan)
+	}()
+	// This will wait for the command to finish
+	cmd.Execute(context.Background())
+	d.testWait()
+	if diff := cmp.Diff(d.expectStdoutStr, d.stdoutStrBuilder.String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	cmd.Finish()
+}
+
+func TestPTY(t *testing.T) {
+	d := &testJobReadData{testName: "Terminal size", command: "/usr/bin/bash",
+		args: []string{"-c", "test -t 0 && test -t 1 && stty size"},
+		// Terminal translates new line
+		expectStdoutStr: "24 80\r\n"}
+	t.Logf("Executing test: %s", d.testName)
+	d.testStartRead()
+	cmd, err := newTestCommand(t, d.command, d.args,
+		WithStdoutChan(d.stdoutChan),
+		WithStderrChan(d.stderrChan),
+		WithPTY(24, 80))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// This will wait for the command to finish
+	cmd.Execute(context.Background())
+	d.testWait()
+	if diff := cmp.Diff(d.expectStdoutStr, d.stdoutStrBuilder.String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	cmd.Finish()
+}
+
+func TestParseSignal(t *testing.T) {
+	for _, d := range []struct {
+		name        string
+		expectSig   syscall.Signal
+		expectError bool
+	}{
+		{name: "HUP", expectSig: syscall.SIGHUP},
+		{name: "sigusr1", expectSig: syscall.SIGUSR1},
+		{name: "SIGTERM", expectSig: syscall.SIGTERM},
+		{name: "FOO", expectError: true},
+	} {
+		sig, err := ParseSignal(d.name)
+		if diff := cmp.Diff(d.expectError, err != nil); diff != "" {
+			t.Errorf("Unexpected result for %s: %s", d.name, diff)
+		}
+		if diff := cmp.Diff(d.expectSig, sig); diff != "" {
+			t.Errorf("Unexpected result for %s: %s", d.name, diff)
+		}
+	}
+}
+
+func TestStop(t *testing.T) {
+	testData := []struct {
+		testName      string
+		script        string
+		expectOutcome StopOutcome
+	}{
+		{
+			testName:      "Exits on stop signal",
+			script:        "trap 'exit 3' TERM; sleep 10 & wait",
+			expectOutcome: StopOutcomeGraceful,
+		},
+		{
+			testName:      "Ignores stop signal",
+			script:        "trap '' TERM; sleep 10",
+			expectOutcome: StopOutcomeKilled,
+		},
+	}
+	for _, d := range testData {
+		t.Logf("Executing test: %s", d.testName)
+		cmd, err := newTestCommand(t, "/usr/bin/bash", []string{"-c", d.script},
+			WithStopPolicy(StopPolicy{Signal: syscall.SIGTERM, GracePeriod: time.Second}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		// Give the traps a chance to be set
+		time.AfterFunc(500*time.Millisecond, cancel)
+		// This will wait for the command to be stopped
+		cmd.Execute(ctx)
+		if diff := cmp.Diff(d.expectOutcome, cmd.GetStopOutcome()); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+		cmd.Finish()
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	cmd, err := newTestCommand(t, "sleep", []string{"10"}, WithTimeout(200*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// This will wait for the command to time out
+	cmd.Execute(context.Background())
+	if diff := cmp.Diff(true, cmd.IsTimedOut()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(TerminationReasonTimedOut, cmd.GetTerminationReason()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(StopOutcomeKilled, cmd.GetStopOutcome()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	cmd.Finish()
+}
+
+func TestPause(t *testing.T) {
+	stdoutChan := make(ReadChannel)
+	cmd, err := newTestCommand(t, "/usr/bin/bash",
+		[]string{"-c", "trap 'exit 3' TERM; while true; do echo x; sleep 0.05; done"},
+		WithStdoutChan(stdoutChan),
+		WithStopPolicy(StopPolicy{Signal: syscall.SIGTERM, GracePeriod: time.Second}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var outputBytes atomic.Int64
+	go func() {
+		for data := range stdoutChan {
+			outputBytes.Add(int64(len(data)))
+		}
+	}()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cmd.Execute(ctx)
+		close(done)
+	}()
+	time.Sleep(300 * time.Millisecond)
+	if err := cmd.Pause(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(true, cmd.IsPaused()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if err := cmd.Pause(); err == nil {
+		t.Errorf("Expected error pausing paused command")
+	}
+	// Let the output written before freezing get read
+	time.Sleep(100 * time.Millisecond)
+	pausedBytes := outputBytes.Load()
+	time.Sleep(300 * time.Millisecond)
+	if diff := cmp.Diff(pausedBytes, outputBytes.Load()); diff != "" {
+		t.Errorf("Unexpected output while paused: %s", diff)
+	}
+	if err := cmd.Resume(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if outputBytes.Load() == pausedBytes {
+		t.Errorf("No output after resume")
+	}
+	// Stopping a paused command resumes it to deliver the stop signal
+	if err := cmd.Pause(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cancel()
+	<-done
+	if diff := cmp.Diff(StopOutcomeGraceful, cmd.GetStopOutcome()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	cmd.Finish()
+}
+
+func TestStats(t *testing.T) {
+	requireRoot(t)
+	cmd, err := NewCommand("/usr/bin/bash",
+		[]string{"-c", "for i in $(seq 100000); do :; done"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := cmd.Stats(); err == nil {
+		t.Errorf("Expected error getting stats before execution")
+	}
+	cmd.Execute(context.Background())
+	// Totals are kept till finish
+	stats, err := cmd.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.CPUUsageUsec == 0 {
+		t.Errorf("Unexpected zero cpu usage")
+	}
+	cmd.Finish()
+	if _, err := cmd.Stats(); err == nil {
+		t.Errorf("Expected error getting stats after finish")
+	}
+}
+
+func TestOOMKill(t *testing.T) {
+	requireRoot(t)
+	requireController(t, "memory")
+	t.Logf("Executing test: Exceeding memory limit")
+	// Swap is disabled so that exceeding the limit gets killed, and
+	// the whole group is killed so that bash does not outlive tail
+	swapMaxKB := int64(0)
+	cmd, err := NewCommand("/usr/bin/bash",
+		[]string{"-c", "head -c 64m /dev/zero | tail"},
+		WithMemorySpec(cgroups.MemorySpec{MaxKB: 8 * 1024, SwapMaxKB: &swapMaxKB,
+			OOMGroup: true}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer cmd.Finish()
+	cmd.Execute(context.Background())
+	if diff := cmp.Diff(TerminationReasonOOMKilled, cmd.GetTerminationReason()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestKillDescendants(t *testing.T) {
+	requireRoot(t)
+	testData := []struct {
+		testName string
+		// Command is killed if set, otherwise it exits leaving
+		// the descendant behind for finish to kill
+		kill bool
+	}{
+		{testName: "Killed command", kill: true},
+		{testName: "Exited command", kill: false},
+	}
+	for _, d := range testData {
+		t.Logf("Executing test: %s", d.testName)
+		pidFile := filepath.Join(t.TempDir(), "pid")
+		script := "setsid sleep 100 >/dev/null 2>&1 & echo $! > " + pidFile
+		if d.kill {
+			script += "; sleep 100"
+		}
+		// The descendant leaves the process group of the command
+		cmd, err := NewCommand("/usr/bin/bash", []string{"-c", script})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		done := make(chan struct{})
+		go func() {
+			cmd.Execute(context.Background())
+			close(done)
+		}()
+		var pid int
+		for i := 0; i < 50 && pid == 0; i++ {
+			time.Sleep(20 * time.Millisecond)
+			content, _ := os.ReadFile(pidFile)
+			pid, _ = strconv.Atoi(strings.TrimSpace(string(content)))
+		}
+		if pid == 0 {
+			t.Fatalf("Descendant did not start")
+		}
+		if d.kill {
+			if err := cmd.Kill(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		<-done
+		if err := cmd.Finish(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		// Killed descendant is gone or waits to be reaped
+		content, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err == nil && !strings.Contains(string(content), ") Z ") {
+			t.Errorf("Descendant %d is still running: %s", pid, content)
+		}
+	}
+}
+
+func TestProcessExecutor(t *testing.T) {
+	d := &testJobReadData{testName: "Plain process", command: "/usr/bin/bash",
+		args: []string{"-c", "echo $$ $EUID; sleep 0.3"}}
+	t.Logf("Executing test: %s", d.testName)
+	// Nothing confines the commands, so these never run as root
+	var credential *syscall.Credential
+	uid := os.Geteuid()
+	if uid == 0 {
+		if _, err := NewProcessExecutor(nil); err == nil {
+			t.Errorf("Expected error for process executor run as root")
+		}
+		credential = &syscall.Credential{Uid: 65534, Gid: 65534}
+		uid = 65534
+	}
+	if _, err := NewProcessExecutor(&syscall.Credential{}); err == nil {
+		t.Errorf("Expected error for commands run as root")
+	}
+	executor, err := NewProcessExecutor(credential)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	d.testStartRead()
+	// Isolation options are ignored, so no privileges are needed
+	job, err := executor.NewJob(d.command, d.args,
+		WithStdoutChan(d.stdoutChan),
+		WithStderrChan(d.stderrChan),
+		WithUsePIDNS(),
+		WithNewRootBase(t.TempDir()),
+		WithMemoryLimit(16*1024))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		job.Execute(context.Background())
+		close(done)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err := job.Pause(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := job.Resume(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	<-done
+	d.testWait()
+	fields := strings.Fields(d.stdoutStrBuilder.String())
+	if len(fields) != 2 {
+		t.Fatalf("Unexpected output: %q", d.stdoutStrBuilder.String())
+	}
+	// Not the first process of a new PID namespace
+	if fields[0] == "1" {
+		t.Errorf("Unexpected PID namespace")
+	}
+	if diff := cmp.Diff(strconv.Itoa(uid), fields[1]); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(TerminationReasonExited, job.GetTerminationReason()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if _, err := job.Stats(); err == nil {
+		t.Errorf("Expected error getting stats of plain process")
+	}
+	if err := job.Finish(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/troplet/pkg/exec/cgroups"
+)
+
+// Returned for stats of jobs run without a control group
+var ErrNoControlGroup = errors.New("command has no control group")
+
+// Job is a command created by an Executor. *Command is the job
+// of the executors in this package.
+type Job interface {
+	GetID() string
+	// Address of the job in its network, nil if none
+	GetIP() net.IP
+	// Blocks till the job has terminated
+	Execute(ctx context.Context) error
+	IsTerminated() bool
+	GetExitError() (error, error)
+	GetExitCode() (int, error)
+	GetTerminationReason() TerminationReason
+	GetStopOutcome() StopOutcome
+	IsTimedOut() bool
+	Signal(sig syscall.Signal) error
+	Stop(gracePeriod time.Duration) error
+	Pause() error
+	Resume() error
+	Resize(rows, cols uint16) error
+	Stats() (*cgroups.Stats, error)
+	// Must be called once the job has terminated
+	Finish() error
+}
+
+// Executor creates jobs, not yet executed, of commands with given
+// options
+type Executor interface {
+	NewJob(name string, args []string, options ...CommandOption) (Job, error)
+}
+
+// CommandSpec holds what the options set for running a command,
+// for executors that run commands on their own
+type CommandSpec struct {
+	Name       string
+	Args       []string
+	StdoutChan ReadChannel
+	StderrChan ReadChannel
+	StdinChan  WriteChannel
+	StopPolicy StopPolicy
+	Timeout    time.Duration
+}
+
+// Returns spec of the command with given name, args and options
+func NewCommandSpec(name string, args []string, options ...CommandOption) CommandSpec {
+	c := &Command{stopPolicy: StopPolicy{Signal: syscall.SIGKILL}}
+	for _, option := range options {
+		option(c)
+	}
+
+	return CommandSpec{Name: name, Args: args, StdoutChan: c.stdoutChan,
+		StderrChan: c.stderrChan, StdinChan: c.stdinChan,
+		StopPolicy: c.stopPolicy, Timeout: c.timeout}
+}
+
+type isolatedExecutor struct {
+	options []CommandOption
+}
+
+// Returns executor of commands isolated as per their options, in
+// their own namespaces, root and control group. Given options are
+// applied to every command before its own.
+func NewIsolatedExecutor(options ...CommandOption) Executor {
+	return &isolatedExecutor{options}
+}
+
+func (e *isolatedExecutor) NewJob(name string, args []string,
+	options ...CommandOption) (Job, error) {
+	return NewCommand(name, args, append(e.options[:len(e.options):len(e.options)],
+		options...)...)
+}
+
+type processExecutor struct {
+	credential *syscall.Credential
+}
+
+// Returns executor of commands as plain processes, which needs no
+// privileges. Options for namespaces, new root and control group are
+// ignored, thus no limits apply and no resource usage is accounted.
+// Pausing stops the process group of the command instead of freezing
+// it. Commands run as the user and group of given credential if any,
+// otherwise as the user of this process, which must not be root since
+// nothing confines the commands.
+func NewProcessExecutor(credential *syscall.Credential) (Executor, error) {
+	if credential == nil && os.Geteuid() == 0 {
+		return nil, errors.New("process executor needs a user other than root to run commands as")
+	}
+	if credential != nil && credential.Uid == 0 {
+		return nil, errors.New("process executor cannot run commands as root")
+	}
+
+	return processExecutor{credential}, nil
+}
+
+func (e processExecutor) NewJob(name string, args []string,
+	options ...CommandOption) (Job, error) {
+	return NewCommand(name, args, append(options, withoutIsolation(e.credential))...)
+}
+
+// Option to run the command as a plain process with given credential
+// if any, ignoring the isolation options
+func withoutIsolation(credential *syscall.Credential) CommandOption {
+	return func(c *Command) {
+		c.unisolated = true
+		c.credential = credential
+	}
+}
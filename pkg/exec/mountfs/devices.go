@@ -0,0 +1,122 @@
+package mountfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Device is a block device identified by its numbers
+type Device struct {
+	MajorNum int32
+	MinorNum int32
+}
+
+func (d Device) String() string {
+	return fmt.Sprintf("%d:%d", d.MajorNum, d.MinorNum)
+}
+
+// Returns the disks backing the given mount root and the directories
+// bind mounted under it. Filesystems without a block device, such as
+// tmpfs, are skipped. Partitions are resolved to their disks since IO
+// controls apply to whole disks only.
+func GetBackingDevices(mountRoot string) ([]Device, error) {
+	// Mount root is created only when a command starts
+	root, err := getExistingParent(mountRoot)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{root}
+	for _, d := range fsInfo {
+		if d.flags&syscall.MS_BIND != 0 {
+			paths = append(paths, d.source)
+		}
+	}
+	ret := []Device{}
+	for _, path := range paths {
+		device, err := getFilesystemDevice(path)
+		if errors.Is(err, fs.ErrNotExist) && path != root {
+			// Not mounted either
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		disk, err := GetDisk(device)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(ret, disk) {
+			ret = append(ret, disk)
+		}
+	}
+
+	return ret, nil
+}
+
+// Returns the disk of the given block device, the device itself
+// unless it is a partition. Fails with fs.ErrNotExist if there is
+// no such block device.
+func GetDisk(device Device) (Device, error) {
+	sysPath := filepath.Join("/sys/dev/block", device.String())
+	devicePath, err := filepath.EvalSymlinks(sysPath)
+	if err != nil {
+		return Device{}, fmt.Errorf("failed to find block device %s: %w", device, err)
+	}
+	if _, err := os.Stat(filepath.Join(devicePath, "partition")); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return device, nil
+		}
+		return Device{}, fmt.Errorf("failed to check partition %s: %w", device, err)
+	}
+	diskDevPath := filepath.Join(filepath.Dir(devicePath), "dev")
+	content, err := os.ReadFile(diskDevPath)
+	if err != nil {
+		return Device{}, fmt.Errorf("failed reading %s: %w", diskDevPath, err)
+	}
+	var disk Device
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(content)), "%d:%d",
+		&disk.MajorNum, &disk.MinorNum); err != nil {
+		return Device{}, fmt.Errorf("failed parsing %s: %w", diskDevPath, err)
+	}
+
+	return disk, nil
+}
+
+// Returns device of the filesystem the path is on
+func getFilesystemDevice(path string) (Device, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return Device{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	return Device{MajorNum: int32(unix.Major(stat.Dev)),
+		MinorNum: int32(unix.Minor(stat.Dev))}, nil
+}
+
+// Returns the closest existing parent of the path, the path
+// itself if it exists
+func getExistingParent(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path on %s: %w", path, err)
+	}
+	for absPath != "/" {
+		if _, err := os.Stat(absPath); !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		absPath = filepath.Dir(absPath)
+	}
+
+	return absPath, nil
+}
@@ -0,0 +1,23 @@
+package mountfs
+
+import (
+	"syscall"
+)
+
+// Mounter performs the mounts of the new root. The real one is used
+// by default, a fake allows preparing the new root without privileges
+// such as in tests.
+type Mounter interface {
+	Mount(source, target, fstype string, flags uintptr, data string) error
+	Unmount(target string, flags int) error
+}
+
+type syscallMounter struct{}
+
+func (syscallMounter) Mount(source, target, fstype string, flags uintptr, data string) error {
+	return syscall.Mount(source, target, fstype, flags, data)
+}
+
+func (syscallMounter) Unmount(target string, flags int) error {
+	return syscall.Unmount(target, flags)
+}
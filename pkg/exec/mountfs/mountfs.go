@@ -27,14 +27,34 @@ var fsInfo = []struct {
 	{"", "/sys/fs/cgroup", "cgroup2", 0, 500},
 }
 
+// Directory under which new roots must be, replaced only in tests
+var mountRootPrefix = "/home"
+
 type MountFSManager struct {
 	mountRoot               string
 	mountRootAlreadyCreated bool
 	mountedPrefixes         []string
+	mounter                 Mounter
 }
 
-func NewMountFSManager(mountRoot string) *MountFSManager {
-	return &MountFSManager{mountRoot, false, []string{}}
+// Option of the mount manager
+type Option func(*MountFSManager)
+
+// Option to mount with given mounter instead of the real one
+func WithMounter(mounter Mounter) Option {
+	return func(m *MountFSManager) {
+		m.mounter = mounter
+	}
+}
+
+func NewMountFSManager(mountRoot string, opts ...Option) *MountFSManager {
+	m := &MountFSManager{mountRoot: mountRoot, mountedPrefixes: []string{},
+		mounter: syscallMounter{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 func (m *MountFSManager) GetMountRoot() string {
@@ -51,8 +71,8 @@ func (m *MountFSManager) Mount() error {
 		return err
 	}
 	// Lets make sure that the prefix is some home directory
-	// for safety reasons
-	if !strings.HasPrefix(absPath, "/home") {
+	// for safety reasons, whatever the mounter
+	if !strings.HasPrefix(absPath, mountRootPrefix+"/") {
 		return fmt.Errorf("mount directory provided must be anywhere under user's home")
 	}
 
@@ -70,7 +90,7 @@ func (m *MountFSManager) Mount() error {
 		if err := os.MkdirAll(target, d.permissions); err != nil {
 			return fmt.Errorf("failed to create %s: %w", target, err)
 		}
-		if err := syscall.Mount(d.source, target, d.fstype, d.flags, ""); err != nil {
+		if err := m.mounter.Mount(d.source, target, d.fstype, d.flags, ""); err != nil {
 			return fmt.Errorf("failed to mount %s: %w", target, err)
 		}
 		// Remember what got mounted
@@ -88,7 +108,7 @@ func (m *MountFSManager) Finish() {
 	// Unmount only the directories that were mounted
 	for i := len(m.mountedPrefixes) - 1; i >= 0; i-- {
 		target := filepath.Join(m.mountRoot, m.mountedPrefixes[i])
-		if err := syscall.Unmount(target, 0); err != nil {
+		if err := m.mounter.Unmount(target, 0); err != nil {
 			// TODO: Log error and continue
 		}
 	}
@@ -0,0 +1,58 @@
+package mountfs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/troplet/internal/mountfstest"
+)
+
+func TestMountFS(t *testing.T) {
+	mounter := mountfstest.NewFakeMounter()
+	dir := t.TempDir()
+	mountRoot := filepath.Join(dir, "root")
+	defer func(prefix string) { mountRootPrefix = prefix }(mountRootPrefix)
+	mountRootPrefix = dir
+	m := NewMountFSManager(mountRoot, WithMounter(mounter))
+	if err := m.Mount(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mounts := mounter.Mounts()
+	if diff := cmp.Diff(len(fsInfo), len(mounts)); diff != "" {
+		t.Fatalf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(mountfstest.FakeMount{Source: "/usr/bin",
+		Target: filepath.Join(mountRoot, "usr/bin"),
+		Flags:  syscall.MS_BIND | syscall.MS_RDONLY}, mounts[0]); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff(mountfstest.FakeMount{Source: "proc",
+		Target: filepath.Join(mountRoot, "proc"), FSType: "proc"}, mounts[6]); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Mount points get created
+	if _, err := os.Stat(filepath.Join(mountRoot, "lib64")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	m.Finish()
+	if diff := cmp.Diff([]string{}, mounter.Mounted(), cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if _, err := os.Stat(mountRoot); !os.IsNotExist(err) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// Mounts are allowed only under home, with any mounter
+	mountRootPrefix = "/home"
+	m = NewMountFSManager(mountRoot, WithMounter(mounter))
+	if err := m.Mount(); err == nil {
+		t.Errorf("Expected error for mount root outside home")
+	}
+	if diff := cmp.Diff(len(fsInfo), len(mounter.Mounts())); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
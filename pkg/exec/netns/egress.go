@@ -0,0 +1,136 @@
+package netns
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Network mode of a namespace
+type Mode string
+
+const (
+	// No interface up, not even loopback
+	ModeNone Mode = "none"
+	// Only loopback
+	ModeLoopback Mode = "loopback"
+	// Connected to the bridge, reaching the host and the other
+	// namespaces on it but nothing beyond
+	ModeBridged Mode = "bridged"
+	// Connected to the bridge with outbound traffic routed and
+	// masqueraded behind the address of the host
+	ModeNAT Mode = "nat"
+)
+
+// Modes in the order of increasing reach
+var Modes = []Mode{ModeNone, ModeLoopback, ModeBridged, ModeNAT}
+
+func (m Mode) Validate() error {
+	if !slices.Contains(Modes, m) {
+		return fmt.Errorf("invalid network mode %q", m)
+	}
+
+	return nil
+}
+
+// Checks if the mode connects to a network
+func (m Mode) IsConnected() bool {
+	return m == ModeBridged || m == ModeNAT
+}
+
+// Bounds of the egress rules of a namespace. Every port of a rule
+// takes an nftables rule per protocol.
+const (
+	MaxEgressRules = 32
+	MaxEgressPorts = 16
+)
+
+// Destination a namespace is allowed to send to. Ports apply to both
+// TCP and UDP, every port and protocol is allowed if there are none.
+type EgressRule struct {
+	Network *net.IPNet
+	Ports   []uint16
+}
+
+// Returns rule of given IPv4 CIDR and ports, sorted without duplicates
+func NewEgressRule(cidr string, ports []uint16) (EgressRule, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return EgressRule{}, fmt.Errorf("invalid egress network: %w", err)
+	}
+	if network.IP.To4() == nil {
+		return EgressRule{}, fmt.Errorf("egress network %s is not IPv4", cidr)
+	}
+	if len(ports) > MaxEgressPorts {
+		return EgressRule{}, fmt.Errorf("egress rule has %d ports, at most %d allowed",
+			len(ports), MaxEgressPorts)
+	}
+	for _, port := range ports {
+		if port == 0 {
+			return EgressRule{}, fmt.Errorf("invalid egress port 0")
+		}
+	}
+	ports = slices.Clone(ports)
+	slices.Sort(ports)
+
+	return EgressRule{Network: network, Ports: slices.Compact(ports)}, nil
+}
+
+// Returns the rules without duplicates, failing if there are more
+// than MaxEgressRules left
+func CompactEgressRules(rules []EgressRule) ([]EgressRule, error) {
+	ret := []EgressRule{}
+	for _, rule := range rules {
+		if !slices.ContainsFunc(ret, rule.Equal) {
+			ret = append(ret, rule)
+		}
+	}
+	if len(ret) > MaxEgressRules {
+		return nil, fmt.Errorf("%d egress rules, at most %d allowed",
+			len(ret), MaxEgressRules)
+	}
+
+	return ret, nil
+}
+
+// Checks if the rules allow the same destinations
+func (r EgressRule) Equal(other EgressRule) bool {
+	return r.Network.String() == other.Network.String() &&
+		slices.Equal(r.Ports, other.Ports)
+}
+
+// Checks if every destination of the rule is allowed by the other
+func (r EgressRule) IsWithin(other EgressRule) bool {
+	ones, _ := r.Network.Mask.Size()
+	otherOnes, _ := other.Network.Mask.Size()
+	if ones < otherOnes || !other.Network.Contains(r.Network.IP) {
+		return false
+	}
+	if len(other.Ports) == 0 {
+		return true
+	}
+	if len(r.Ports) == 0 {
+		return false
+	}
+	for _, port := range r.Ports {
+		if !slices.Contains(other.Ports, port) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r EgressRule) String() string {
+	if len(r.Ports) == 0 {
+		return r.Network.String()
+	}
+	ports := []string{}
+	for _, port := range r.Ports {
+		ports = append(ports, strconv.Itoa(int(port)))
+	}
+
+	return r.Network.String() + ":" + strings.Join(ports, ",")
+}
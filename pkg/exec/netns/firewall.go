@@ -0,0 +1,364 @@
+package netns
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/nftables"
+	"github.com/google/nftables/binaryutil"
+	"github.com/google/nftables/expr"
+	"golang.org/x/sys/unix"
+)
+
+// Prefixes of the table of a network and the chains of its namespaces
+const (
+	tablePrefix = "troplet_"
+	chainPrefix = "egress_"
+)
+
+// Firewall of a network, an nftables table of its own. Traffic from
+// a namespace is looked up by its address in verdict maps, one for
+// traffic to the host and one for traffic routed beyond it. A
+// namespace with an egress allow-list jumps to a chain of its own,
+// which drops what is not allowed, also when routed back to the
+// bridge. The network is IPv4 only, IPv6 from the bridge is dropped
+// so that link-local addresses do not get around the rules. Frames
+// switched by the bridge never reach these rules, thus a bridge table
+// drops the ones from namespaces with an allow-list to the other
+// namespaces.
+type firewall struct {
+	bridge     string
+	table      *nftables.Table
+	inputMap   *nftables.Set
+	forwardMap *nftables.Set
+	// Addresses masqueraded on their way out
+	natSet *nftables.Set
+	// Host ends of veth pairs of the namespaces with an allow-list
+	bridgeTable   *nftables.Table
+	restrictedSet *nftables.Set
+	// Serializes changes of the verdict maps and the chains
+	lock sync.Mutex
+}
+
+// Creates the table of the network if missing. Rules of its base
+// chains are recreated, while verdicts and chains of namespaces
+// are kept since these may outlive the process.
+func newFirewall(bridge string) (*firewall, error) {
+	c, err := nftables.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nftables: %w", err)
+	}
+	f := &firewall{bridge: bridge}
+	f.table = c.AddTable(&nftables.Table{Family: nftables.TableFamilyINet,
+		Name: tablePrefix + bridge})
+	f.inputMap = &nftables.Set{Table: f.table, Name: "input", IsMap: true,
+		KeyType: nftables.TypeIPAddr, DataType: nftables.TypeVerdict}
+	f.forwardMap = &nftables.Set{Table: f.table, Name: "forward", IsMap: true,
+		KeyType: nftables.TypeIPAddr, DataType: nftables.TypeVerdict}
+	f.natSet = &nftables.Set{Table: f.table, Name: "nat", KeyType: nftables.TypeIPAddr}
+	for _, set := range []*nftables.Set{f.inputMap, f.forwardMap, f.natSet} {
+		if err := c.AddSet(set, nil); err != nil {
+			return nil, fmt.Errorf("failed to add set %s: %w", set.Name, err)
+		}
+	}
+	accept := nftables.ChainPolicyAccept
+	for _, v := range []struct {
+		name       string
+		hook       *nftables.ChainHook
+		verdictMap *nftables.Set
+	}{
+		{"input", nftables.ChainHookInput, f.inputMap},
+		{"forward", nftables.ChainHookForward, f.forwardMap},
+	} {
+		chain := c.AddChain(&nftables.Chain{Name: v.name, Table: f.table,
+			Type: nftables.ChainTypeFilter, Hooknum: v.hook,
+			Priority: nftables.ChainPriorityFilter, Policy: &accept})
+		c.FlushChain(chain)
+		// Replies to connections made to the namespaces
+		c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+			&expr.Ct{Register: 1, Key: expr.CtKeySTATE},
+			&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4,
+				Mask: binaryutil.NativeEndian.PutUint32(expr.CtStateBitESTABLISHED |
+					expr.CtStateBitRELATED),
+				Xor: binaryutil.NativeEndian.PutUint32(0)},
+			&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: []byte{0, 0, 0, 0}},
+			&expr.Verdict{Kind: expr.VerdictAccept},
+		}})
+		if v.hook == nftables.ChainHookForward {
+			// Traffic routed back to the bridge, such as sent to the
+			// gateway for another namespace, is subject to the egress
+			// rules of the sender
+			c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+				&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+				&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+				&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV4}},
+				&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader,
+					Offset: 12, Len: 4},
+				&expr.Lookup{SourceRegister: 1, IsDestRegSet: true,
+					SetName: f.inputMap.Name, SetID: f.inputMap.ID},
+			}})
+			// Traffic between the other namespaces, switched by the
+			// bridge, shows up here with br_netfilter
+			c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+				&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+				&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+				&expr.Verdict{Kind: expr.VerdictAccept},
+			}})
+		}
+		c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV4}},
+			&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader,
+				Offset: 12, Len: 4},
+			&expr.Lookup{SourceRegister: 1, IsDestRegSet: true,
+				SetName: v.verdictMap.Name, SetID: v.verdictMap.ID},
+		}})
+		c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+			&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: getIfname(bridge)},
+			&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+			&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV6}},
+			&expr.Verdict{Kind: expr.VerdictDrop},
+		}})
+	}
+	postrouting := c.AddChain(&nftables.Chain{Name: "postrouting", Table: f.table,
+		Type: nftables.ChainTypeNAT, Hooknum: nftables.ChainHookPostrouting,
+		Priority: nftables.ChainPriorityNATSource})
+	c.FlushChain(postrouting)
+	c.AddRule(&nftables.Rule{Table: f.table, Chain: postrouting, Exprs: []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{unix.NFPROTO_IPV4}},
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader,
+			Offset: 12, Len: 4},
+		&expr.Lookup{SourceRegister: 1, SetName: f.natSet.Name, SetID: f.natSet.ID},
+		&expr.Meta{Key: expr.MetaKeyOIFNAME, Register: 1},
+		&expr.Cmp{Op: expr.CmpOpNeq, Register: 1, Data: getIfname(bridge)},
+		&expr.Masq{},
+	}})
+	if err := f.addBridgeTable(c); err != nil {
+		return nil, err
+	}
+	if err := c.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to create nftables table %s: %w", f.table.Name, err)
+	}
+
+	return f, nil
+}
+
+// Adds the table filtering frames switched by the bridge, dropping the
+// ones sent by namespaces with an allow-list to the other namespaces.
+// Replies cannot be told apart without bridge conntrack, so these
+// namespaces cannot be reached from the others either. Frames to the
+// host are not forwarded, and filtered by the other table.
+func (f *firewall) addBridgeTable(c *nftables.Conn) error {
+	f.bridgeTable = c.AddTable(&nftables.Table{Family: nftables.TableFamilyBridge,
+		Name: tablePrefix + f.bridge})
+	f.restrictedSet = &nftables.Set{Table: f.bridgeTable, Name: "restricted",
+		KeyType: nftables.TypeIFName}
+	if err := c.AddSet(f.restrictedSet, nil); err != nil {
+		return fmt.Errorf("failed to add set %s: %w", f.restrictedSet.Name, err)
+	}
+	accept := nftables.ChainPolicyAccept
+	chain := c.AddChain(&nftables.Chain{Name: "forward", Table: f.bridgeTable,
+		Type: nftables.ChainTypeFilter, Hooknum: nftables.ChainHookForward,
+		Priority: nftables.ChainPriorityFilter, Policy: &accept})
+	c.FlushChain(chain)
+	c.AddRule(&nftables.Rule{Table: f.bridgeTable, Chain: chain, Exprs: []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyIIFNAME, Register: 1},
+		&expr.Lookup{SourceRegister: 1, SetName: f.restrictedSet.Name,
+			SetID: f.restrictedSet.ID},
+		&expr.Verdict{Kind: expr.VerdictDrop},
+	}})
+
+	return nil
+}
+
+// Adds rules of the namespace of given id and address. Traffic routed
+// beyond the host is dropped unless in NAT mode, and only what the
+// egress rules allow is let through if there are any.
+func (f *firewall) addNamespace(id string, ip net.IP, mode Mode, egress []EgressRule) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	c, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("failed to open nftables: %w", err)
+	}
+	forward := &expr.Verdict{Kind: expr.VerdictDrop}
+	if mode == ModeNAT {
+		forward = nil
+		if err := c.SetAddElements(f.natSet, []nftables.SetElement{{Key: ip.To4()}}); err != nil {
+			return fmt.Errorf("failed to add %s to nat: %w", ip, err)
+		}
+	}
+	if len(egress) != 0 {
+		chain := c.AddChain(&nftables.Chain{Name: getChainName(id), Table: f.table})
+		for _, rule := range egress {
+			for _, exprs := range getEgressExprs(rule) {
+				c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: exprs})
+			}
+		}
+		c.AddRule(&nftables.Rule{Table: f.table, Chain: chain, Exprs: []expr.Any{
+			&expr.Verdict{Kind: expr.VerdictDrop},
+		}})
+		jump := &expr.Verdict{Kind: expr.VerdictJump, Chain: chain.Name}
+		if err := c.SetAddElements(f.inputMap, []nftables.SetElement{
+			{Key: ip.To4(), VerdictData: jump}}); err != nil {
+			return fmt.Errorf("failed to add %s to input: %w", ip, err)
+		}
+		if err := c.SetAddElements(f.restrictedSet, []nftables.SetElement{
+			{Key: getIfname(getHostVethName(id))}}); err != nil {
+			return fmt.Errorf("failed to add %s to restricted: %w", ip, err)
+		}
+		if forward == nil {
+			forward = jump
+		}
+	}
+	if forward != nil {
+		if err := c.SetAddElements(f.forwardMap, []nftables.SetElement{
+			{Key: ip.To4(), VerdictData: forward}}); err != nil {
+			return fmt.Errorf("failed to add %s to forward: %w", ip, err)
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return fmt.Errorf("failed to add nftables rules of %s: %w", ip, err)
+	}
+
+	return nil
+}
+
+// Removes rules of the namespace of given id and address, if any
+func (f *firewall) removeNamespace(id string, ip net.IP) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	c, err := nftables.New()
+	if err != nil {
+		return fmt.Errorf("failed to open nftables: %w", err)
+	}
+	// Elements must be gone before the chain they jump to
+	for _, set := range []*nftables.Set{f.inputMap, f.forwardMap, f.natSet} {
+		elements, err := c.GetSetElements(set)
+		if err != nil {
+			return fmt.Errorf("failed to get elements of %s: %w", set.Name, err)
+		}
+		for _, element := range elements {
+			if !bytes.Equal(element.Key, ip.To4()) {
+				continue
+			}
+			if err := c.SetDeleteElements(set, []nftables.SetElement{
+				{Key: element.Key}}); err != nil {
+				return fmt.Errorf("failed to remove %s from %s: %w", ip, set.Name, err)
+			}
+		}
+	}
+	elements, err := c.GetSetElements(f.restrictedSet)
+	if err != nil {
+		return fmt.Errorf("failed to get elements of %s: %w", f.restrictedSet.Name, err)
+	}
+	ifname := getIfname(getHostVethName(id))
+	for _, element := range elements {
+		if !bytes.Equal(element.Key, ifname) {
+			continue
+		}
+		if err := c.SetDeleteElements(f.restrictedSet, []nftables.SetElement{
+			{Key: element.Key}}); err != nil {
+			return fmt.Errorf("failed to remove %s from %s: %w", ip, f.restrictedSet.Name, err)
+		}
+	}
+	chains, err := c.ListChainsOfTableFamily(f.table.Family)
+	if err != nil {
+		return fmt.Errorf("failed to list nftables chains: %w", err)
+	}
+	for _, chain := range chains {
+		if chain.Table.Name == f.table.Name && chain.Name == getChainName(id) {
+			c.FlushChain(chain)
+			c.DelChain(chain)
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return fmt.Errorf("failed to remove nftables rules of %s: %w", ip, err)
+	}
+
+	return nil
+}
+
+// Returns expressions of the rules accepting traffic to the
+// destinations of the egress rule
+func getEgressExprs(rule EgressRule) [][]expr.Any {
+	daddr := []expr.Any{
+		&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseNetworkHeader,
+			Offset: 16, Len: 4},
+		&expr.Bitwise{SourceRegister: 1, DestRegister: 1, Len: 4,
+			Mask: rule.Network.Mask, Xor: []byte{0, 0, 0, 0}},
+		&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: rule.Network.IP.To4()},
+	}
+	accept := &expr.Verdict{Kind: expr.VerdictAccept}
+	if len(rule.Ports) == 0 {
+		return [][]expr.Any{append(daddr, accept)}
+	}
+	ret := [][]expr.Any{}
+	for _, proto := range []byte{unix.IPPROTO_TCP, unix.IPPROTO_UDP} {
+		for _, port := range rule.Ports {
+			exprs := append([]expr.Any{}, daddr...)
+			exprs = append(exprs,
+				&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1, Data: []byte{proto}},
+				&expr.Payload{DestRegister: 1, Base: expr.PayloadBaseTransportHeader,
+					Offset: 2, Len: 2},
+				&expr.Cmp{Op: expr.CmpOpEq, Register: 1,
+					Data: binaryutil.BigEndian.PutUint16(port)},
+				accept)
+			ret = append(ret, exprs)
+		}
+	}
+
+	return ret
+}
+
+const forwardingPath = "/proc/sys/net/ipv4/ip_forward"
+
+// Enables routing of IPv4 traffic of the host, needed for NAT mode.
+// This applies to the whole host and stays on once the process exits.
+// Returns whether forwarding was off before.
+func EnableForwarding() (bool, error) {
+	enabled, err := isForwardingEnabled()
+	if err != nil || enabled {
+		return false, err
+	}
+	if err := os.WriteFile(forwardingPath, []byte("1"), 0644); err != nil {
+		return false, fmt.Errorf("failed to enable forwarding: %w", err)
+	}
+
+	return true, nil
+}
+
+func isForwardingEnabled() (bool, error) {
+	content, err := os.ReadFile(forwardingPath)
+	if err != nil {
+		return false, fmt.Errorf("failed reading %s: %w", forwardingPath, err)
+	}
+
+	return strings.TrimSpace(string(content)) == "1", nil
+}
+
+func getChainName(id string) string {
+	return chainPrefix + id
+}
+
+// Interface names are matched zero padded
+func getIfname(name string) []byte {
+	ifname := make([]byte, unix.IFNAMSIZ)
+	copy(ifname, name)
+
+	return ifname
+}
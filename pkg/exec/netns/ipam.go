@@ -0,0 +1,119 @@
+package netns
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// IPAM leases addresses of an IPv4 subnet. Every lease is persisted
+// as a file named after the address, holding the id of its owner,
+// so that leases of commands outliving the process are not reused.
+type IPAM struct {
+	subnet *net.IPNet
+	dir    string
+	// Serializes allocations within the process, files guard
+	// against other processes
+	lock sync.Mutex
+}
+
+// Returns IPAM of given subnet with the leases persisted under dir.
+// Network address, first address reserved for the gateway and
+// broadcast address are never leased.
+func NewIPAM(subnet *net.IPNet, dir string) (*IPAM, error) {
+	if subnet.IP.To4() == nil {
+		return nil, fmt.Errorf("subnet %s is not IPv4", subnet)
+	}
+	if ones, bits := subnet.Mask.Size(); bits-ones < 2 {
+		return nil, fmt.Errorf("subnet %s too small", subnet)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create lease directory: %w", err)
+	}
+
+	return &IPAM{subnet: subnet, dir: dir}, nil
+}
+
+// Returns the gateway address of the subnet
+func (i *IPAM) GetGateway() net.IP {
+	return addToIP(i.subnet.IP.To4(), 1)
+}
+
+// Leases the lowest free address to given owner
+func (i *IPAM) Allocate(id string) (net.IP, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	ones, bits := i.subnet.Mask.Size()
+	size := uint32(1) << (bits - ones)
+	for offset := uint32(2); offset < size-1; offset++ {
+		ip := addToIP(i.subnet.IP.To4(), offset)
+		file, err := os.OpenFile(i.getLeasePath(ip), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lease %s: %w", ip, err)
+		}
+		_, err = file.WriteString(id)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(i.getLeasePath(ip))
+			return nil, fmt.Errorf("failed to lease %s: %w", ip, err)
+		}
+
+		return ip, nil
+	}
+
+	return nil, fmt.Errorf("no free address in %s", i.subnet)
+}
+
+// Releases the lease of given address, if any
+func (i *IPAM) Release(ip net.IP) error {
+	err := os.Remove(i.getLeasePath(ip))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release %s: %w", ip, err)
+	}
+
+	return nil
+}
+
+// Returns owners of the leased addresses
+func (i *IPAM) GetLeases() (map[string]net.IP, error) {
+	entries, err := os.ReadDir(i.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading leases: %w", err)
+	}
+	leases := map[string]net.IP{}
+	for _, entry := range entries {
+		ip := net.ParseIP(entry.Name()).To4()
+		if ip == nil || !i.subnet.Contains(ip) {
+			// Not a lease, or of a previously configured subnet
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(i.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed reading lease: %w", err)
+		}
+		leases[strings.TrimSpace(string(content))] = ip
+	}
+
+	return leases, nil
+}
+
+func (i *IPAM) getLeasePath(ip net.IP) string {
+	return filepath.Join(i.dir, ip.String())
+}
+
+func addToIP(ip net.IP, offset uint32) net.IP {
+	ret := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ret, binary.BigEndian.Uint32(ip.To4())+offset)
+
+	return ret
+}
@@ -0,0 +1,67 @@
+package netns
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIPAM(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.99.0.0/29")
+	dir := t.TempDir()
+	ipam, err := NewIPAM(subnet, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("10.99.0.1", ipam.GetGateway().String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Gateway and broadcast are never leased
+	got := []string{}
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		ip, err := ipam.Allocate(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got = append(got, ip.String())
+	}
+	if diff := cmp.Diff([]string{"10.99.0.2", "10.99.0.3", "10.99.0.4", "10.99.0.5",
+		"10.99.0.6"}, got); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if _, err := ipam.Allocate("f"); err == nil {
+		t.Errorf("Expected error for exhausted subnet")
+	}
+	// Released address is leased again
+	if err := ipam.Release(net.ParseIP("10.99.0.3")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ip, err := ipam.Allocate("f")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("10.99.0.3", ip.String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Leases are persisted
+	ipam, err = NewIPAM(subnet, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	leases, err := ipam.GetLeases()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(5, len(leases)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("10.99.0.3", leases["f"].String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Subnet with no address to lease
+	_, subnet, _ = net.ParseCIDR("10.99.0.0/31")
+	if _, err := NewIPAM(subnet, dir); err == nil {
+		t.Errorf("Expected error for too small subnet")
+	}
+}
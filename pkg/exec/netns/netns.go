@@ -0,0 +1,153 @@
+// Package netns creates network namespaces of commands, optionally
+// connected to a host bridge over veth pairs with addresses leased
+// from the subnet of the bridge.
+package netns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// Name of the interface of the command connected to the bridge
+const InterfaceName = "eth0"
+
+// Namespace is a network namespace held open by a file descriptor.
+// Processes are started in it via Do.
+type Namespace struct {
+	handle netns.NsHandle
+	// Set if connected to a network
+	network  *Network
+	id       string
+	hostVeth string
+	ip       net.IP
+}
+
+// Creates a network namespace with only loopback, which is brought
+// up if requested
+func NewNamespace(loopback bool) (*Namespace, error) {
+	handle, err := newHandle()
+	if err != nil {
+		return nil, err
+	}
+	ns := &Namespace{handle: handle}
+	if !loopback {
+		return ns, nil
+	}
+	if err := ns.configure(func(h *netlink.Handle) error {
+		return setLinkUp(h, "lo")
+	}); err != nil {
+		ns.Close()
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// Returns the address leased to the namespace, nil if not connected
+// to a network
+func (ns *Namespace) GetIP() net.IP {
+	return ns.ip
+}
+
+// Calls f on a thread switched to the namespace. Processes started
+// by f, such as with exec.Cmd.Start, are created in the namespace.
+func (ns *Namespace) Do(f func() error) error {
+	return runOnThread(func() error {
+		if err := netns.Set(ns.handle); err != nil {
+			return fmt.Errorf("failed to enter network namespace: %w", err)
+		}
+		return f()
+	})
+}
+
+// Disconnects the namespace from its network, releasing its address,
+// and closes it. The namespace goes away once its processes exit.
+func (ns *Namespace) Close() error {
+	var errs []error
+	if ns.hostVeth != "" {
+		// Deleting either end deletes the pair
+		if link, err := netlink.LinkByName(ns.hostVeth); err == nil {
+			if err := netlink.LinkDel(link); err != nil {
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", ns.hostVeth, err))
+			}
+		}
+		ns.hostVeth = ""
+	}
+	if ns.ip != nil {
+		errs = append(errs, ns.network.firewall.removeNamespace(ns.id, ns.ip))
+		errs = append(errs, ns.network.ipam.Release(ns.ip))
+		ns.ip = nil
+	}
+	if ns.handle.IsOpen() {
+		errs = append(errs, ns.handle.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Runs f with a netlink handle of the namespace
+func (ns *Namespace) configure(f func(*netlink.Handle) error) error {
+	h, err := netlink.NewHandleAt(ns.handle)
+	if err != nil {
+		return fmt.Errorf("failed to open netlink in namespace: %w", err)
+	}
+	defer h.Close()
+
+	return f(h)
+}
+
+// Creates a network namespace without switching to it
+func newHandle() (netns.NsHandle, error) {
+	handle := netns.None()
+	err := runOnThread(func() error {
+		var err error
+		// Switches the thread to the new namespace
+		if handle, err = netns.New(); err != nil {
+			return fmt.Errorf("failed to create network namespace: %w", err)
+		}
+		return nil
+	})
+
+	return handle, err
+}
+
+// Calls f on a locked thread which is switched back to its network
+// namespace afterwards. Runs on its own goroutine so that a thread
+// that could not switch back is not reused, it gets terminated once
+// the goroutine exits.
+func runOnThread(f func() error) error {
+	errChan := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		origin, err := netns.Get()
+		if err != nil {
+			errChan <- fmt.Errorf("failed to get network namespace: %w", err)
+			return
+		}
+		defer origin.Close()
+		err = f()
+		if netns.Set(origin) == nil {
+			runtime.UnlockOSThread()
+		}
+		errChan <- err
+	}()
+
+	return <-errChan
+}
+
+func setLinkUp(h *netlink.Handle, name string) error {
+	link, err := h.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("failed to find %s: %w", name, err)
+	}
+	if err := h.LinkSetUp(link); err != nil {
+		return fmt.Errorf("failed to bring up %s: %w", name, err)
+	}
+
+	return nil
+}
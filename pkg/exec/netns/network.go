@@ -0,0 +1,221 @@
+package netns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Prefix of host ends of veth pairs, followed by the start of id
+// of the namespace owning the pair
+const hostVethPrefix = "tr"
+
+// Network is a host bridge, holding the gateway address of its subnet,
+// to which namespaces are connected over veth pairs. Traffic of the
+// namespaces is filtered by nftables rules of the network.
+type Network struct {
+	bridge   *netlink.Bridge
+	subnet   *net.IPNet
+	ipam     *IPAM
+	firewall *firewall
+}
+
+// Creates the bridge with given name if missing, assigns it the first
+// address of the subnet, a CIDR such as "10.88.0.0/16", and brings it
+// up, along with an nftables table of the network. Leases of namespaces
+// are persisted under leaseDir. Leases and rules of namespaces whose
+// veth pair is gone, such as of a previous run, are released. The
+// bridge is deleted on failure if it was created here.
+func NewNetwork(bridgeName, subnet, leaseDir string) (n *Network, err error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet: %w", err)
+	}
+	ipam, err := NewIPAM(ipNet, leaseDir)
+	if err != nil {
+		return nil, err
+	}
+	bridge, created, err := ensureBridge(bridgeName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil && created {
+			if delErr := netlink.LinkDel(bridge); delErr != nil {
+				err = fmt.Errorf("%w, failed to delete bridge %s: %v", err,
+					bridgeName, delErr)
+			}
+		}
+	}()
+	firewall, err := newFirewall(bridgeName)
+	if err != nil {
+		return nil, err
+	}
+	n = &Network{bridge: bridge, subnet: ipNet, ipam: ipam, firewall: firewall}
+	gateway := &netlink.Addr{IPNet: &net.IPNet{IP: ipam.GetGateway(), Mask: ipNet.Mask}}
+	if err := netlink.AddrReplace(bridge, gateway); err != nil {
+		return nil, fmt.Errorf("failed to assign %s to %s: %w", gateway, bridgeName, err)
+	}
+	if err := netlink.LinkSetUp(bridge); err != nil {
+		return nil, fmt.Errorf("failed to bring up %s: %w", bridgeName, err)
+	}
+	if err := n.releaseStaleLeases(); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Returns name of the bridge
+func (n *Network) GetBridgeName() string {
+	return n.bridge.Name
+}
+
+// Returns subnet of the bridge
+func (n *Network) GetSubnet() *net.IPNet {
+	return n.subnet
+}
+
+// Creates a network namespace for given id connected to the bridge in
+// bridged or NAT mode. The namespace gets an address leased from the
+// subnet on its eth0 interface, with the default route via the bridge,
+// and loopback up. If there are egress rules, the namespace can send
+// only to the destinations these allow, including the host. Such a
+// namespace is isolated from the other namespaces on the bridge, since
+// traffic switched by the bridge would bypass the rules.
+func (n *Network) Attach(id string, mode Mode, egress []EgressRule) (ns *Namespace, err error) {
+	if !mode.IsConnected() {
+		return nil, fmt.Errorf("network mode %s does not connect to a network", mode)
+	}
+	if len(egress) > MaxEgressRules {
+		return nil, fmt.Errorf("%d egress rules, at most %d allowed",
+			len(egress), MaxEgressRules)
+	}
+	if mode == ModeNAT {
+		enabled, err := isForwardingEnabled()
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, fmt.Errorf("network mode %s needs IPv4 forwarding, which is off", mode)
+		}
+	}
+	if ns, err = NewNamespace(true); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			ns.Close()
+		}
+	}()
+	ns.network, ns.id = n, id
+	if ns.ip, err = n.ipam.Allocate(id); err != nil {
+		return nil, err
+	}
+	// Rules are in place before the namespace gets connected
+	if err = n.firewall.addNamespace(id, ns.ip, mode, egress); err != nil {
+		return nil, err
+	}
+	veth := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: getHostVethName(id),
+			MasterIndex: n.bridge.Index},
+		PeerName:      InterfaceName,
+		PeerNamespace: netlink.NsFd(ns.handle),
+	}
+	if err = netlink.LinkAdd(veth); err != nil {
+		return nil, fmt.Errorf("failed to create veth pair: %w", err)
+	}
+	ns.hostVeth = veth.Name
+	if len(egress) != 0 {
+		if err = netlink.LinkSetIsolated(veth, true); err != nil {
+			return nil, fmt.Errorf("failed to isolate %s: %w", veth.Name, err)
+		}
+	}
+	if err = netlink.LinkSetUp(veth); err != nil {
+		return nil, fmt.Errorf("failed to bring up %s: %w", veth.Name, err)
+	}
+	err = ns.configure(func(h *netlink.Handle) error {
+		link, err := h.LinkByName(InterfaceName)
+		if err != nil {
+			return fmt.Errorf("failed to find %s: %w", InterfaceName, err)
+		}
+		addr := &netlink.Addr{IPNet: &net.IPNet{IP: ns.ip, Mask: n.subnet.Mask}}
+		if err := h.AddrAdd(link, addr); err != nil {
+			return fmt.Errorf("failed to assign %s: %w", addr, err)
+		}
+		if err := h.LinkSetUp(link); err != nil {
+			return fmt.Errorf("failed to bring up %s: %w", InterfaceName, err)
+		}
+		route := &netlink.Route{LinkIndex: link.Attrs().Index, Gw: n.ipam.GetGateway()}
+		if err := h.RouteAdd(route); err != nil {
+			return fmt.Errorf("failed to add default route: %w", err)
+		}
+		return nil
+	})
+
+	return ns, err
+}
+
+// Releases leases and rules of the namespaces whose host end of veth
+// pair is gone
+func (n *Network) releaseStaleLeases() error {
+	leases, err := n.ipam.GetLeases()
+	if err != nil {
+		return err
+	}
+	for id, ip := range leases {
+		_, err := netlink.LinkByName(getHostVethName(id))
+		if err == nil {
+			continue
+		}
+		if !errors.As(err, &netlink.LinkNotFoundError{}) {
+			return fmt.Errorf("failed to find veth of %s: %w", id, err)
+		}
+		if err := n.firewall.removeNamespace(id, ip); err != nil {
+			return err
+		}
+		if err := n.ipam.Release(ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the bridge with given name, and whether it got created
+// since missing
+func ensureBridge(name string) (*netlink.Bridge, bool, error) {
+	link, err := netlink.LinkByName(name)
+	if err == nil {
+		bridge, ok := link.(*netlink.Bridge)
+		if !ok {
+			return nil, false, fmt.Errorf("%s is not a bridge", name)
+		}
+		return bridge, false, nil
+	}
+	if !errors.As(err, &netlink.LinkNotFoundError{}) {
+		return nil, false, fmt.Errorf("failed to find %s: %w", name, err)
+	}
+	bridge := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: name}}
+	if err := netlink.LinkAdd(bridge); err != nil {
+		return nil, false, fmt.Errorf("failed to create bridge %s: %w", name, err)
+	}
+	// Index is assigned by the kernel
+	link, err = netlink.LinkByName(name)
+	if err != nil {
+		netlink.LinkDel(bridge)
+		return nil, false, fmt.Errorf("failed to find %s: %w", name, err)
+	}
+
+	return link.(*netlink.Bridge), true, nil
+}
+
+// Interface names are limited to 15 characters
+func getHostVethName(id string) string {
+	name := hostVethPrefix + strings.ReplaceAll(id, "-", "")
+
+	return name[:min(len(name), 15)]
+}
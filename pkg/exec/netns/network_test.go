@@ -0,0 +1,466 @@
+package netns
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/nftables"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestNetwork(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Requires root")
+	}
+	leaseDir := t.TempDir()
+	network, err := NewNetwork("trtest0", "10.98.0.0/24", leaseDir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		if link, err := netlink.LinkByName("trtest0"); err == nil {
+			netlink.LinkDel(link)
+		}
+		if c, err := nftables.New(); err == nil {
+			c.DelTable(network.firewall.table)
+			c.DelTable(network.firewall.bridgeTable)
+			c.Flush()
+		}
+	}()
+	server, err := network.Attach("11111111-aaaa", ModeBridged, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer server.Close()
+	client, err := network.Attach("22222222-bbbb", ModeBridged, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer client.Close()
+	if diff := cmp.Diff("10.98.0.2", server.GetIP().String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("10.98.0.3", client.GetIP().String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Sockets belong to the namespace they are created in
+	var listener net.Listener
+	if err := server.Do(func() error {
+		listener, err = net.Listen("tcp", server.GetIP().String()+":8080")
+		return err
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Write([]byte("hello"))
+			conn.Close()
+		}
+	}()
+	var conn net.Conn
+	if err := client.Do(func() error {
+		conn, err = net.Dial("tcp", server.GetIP().String()+":8080")
+		return err
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := conn.Read(buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+	if diff := cmp.Diff("hello", string(buf)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Loopback is up
+	if err := client.Do(func() error {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err == nil {
+			listener.Close()
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// Closing releases the lease and deletes the veth pair
+	if err := client.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := netlink.LinkByName(getHostVethName("22222222-bbbb")); err == nil {
+		t.Errorf("Expected veth to be deleted")
+	}
+	leases, err := network.ipam.GetLeases()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(1, len(leases)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Lease of a namespace gone meanwhile is released on restart
+	if _, err := network.ipam.Allocate("33333333-cccc"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if network, err = NewNetwork("trtest0", "10.98.0.0/24", leaseDir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if leases, err = network.ipam.GetLeases(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"11111111-aaaa"}, getKeys(leases)); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+}
+
+func TestNetworkCleanup(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Requires root")
+	}
+	// A lease that cannot be read fails the creation once the bridge
+	// is up
+	leaseDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(leaseDir, "10.98.2.5"), 0700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		if c, err := nftables.New(); err == nil {
+			for _, family := range []nftables.TableFamily{nftables.TableFamilyINet,
+				nftables.TableFamilyBridge} {
+				c.DelTable(&nftables.Table{Family: family, Name: tablePrefix + "trtest1"})
+			}
+			c.Flush()
+		}
+	}()
+	if _, err := NewNetwork("trtest1", "10.98.2.0/24", leaseDir); err == nil {
+		t.Fatalf("Expected error for unreadable lease")
+	}
+	// Bridge created for the network is deleted
+	if _, err := netlink.LinkByName("trtest1"); err == nil {
+		t.Errorf("Expected bridge to be deleted")
+	}
+	// Existing bridge is kept
+	bridge := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "trtest1"}}
+	if err := netlink.LinkAdd(bridge); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer netlink.LinkDel(bridge)
+	if _, err := NewNetwork("trtest1", "10.98.2.0/24", leaseDir); err == nil {
+		t.Fatalf("Expected error for unreadable lease")
+	}
+	if _, err := netlink.LinkByName("trtest1"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func getKeys(leases map[string]net.IP) []string {
+	keys := []string{}
+	for key := range leases {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func TestEgress(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Requires root")
+	}
+	network, err := NewNetwork("trtest1", "10.96.0.0/24", t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		if link, err := netlink.LinkByName("trtest1"); err == nil {
+			netlink.LinkDel(link)
+		}
+		if c, err := nftables.New(); err == nil {
+			c.DelTable(network.firewall.table)
+			c.DelTable(network.firewall.bridgeTable)
+			c.Flush()
+		}
+	}()
+	// Host listens on the gateway address
+	listeners := []net.Listener{}
+	for _, port := range []string{"8080", "8081"} {
+		listener, err := net.Listen("tcp", "10.96.0.1:"+port)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer listener.Close()
+		listeners = append(listeners, listener)
+	}
+	rule, err := NewEgressRule("10.96.0.1/32", []uint16{8080})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	open, err := network.Attach("11111111-aaaa", ModeBridged, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer open.Close()
+	restricted, err := network.Attach("22222222-bbbb", ModeNAT, []EgressRule{rule})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer restricted.Close()
+	// The other namespace listens on the port allowed on the host
+	if err := open.Do(func() error {
+		listener, err := net.Listen("tcp", "10.96.0.2:8080")
+		if err == nil {
+			listeners = append(listeners, listener)
+		}
+		return err
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer listeners[len(listeners)-1].Close()
+	// Routes the other namespace through the gateway, which the bridge
+	// does not isolate
+	routeViaGateway := func(h *netlink.Handle) error {
+		link, err := h.LinkByName(InterfaceName)
+		if err != nil {
+			return err
+		}
+		return h.RouteAdd(&netlink.Route{LinkIndex: link.Attrs().Index,
+			Dst: &net.IPNet{IP: net.ParseIP("10.96.0.2"), Mask: net.CIDRMask(32, 32)},
+			Gw:  net.ParseIP("10.96.0.1")})
+	}
+	tests := []struct {
+		name        string
+		ns          *Namespace
+		address     string
+		route       func(*netlink.Handle) error
+		expectError bool
+	}{
+		{"Unrestricted to host", open, "10.96.0.1:8081", nil, false},
+		{"Allowed port of host", restricted, "10.96.0.1:8080", nil, false},
+		{"Disallowed port of host", restricted, "10.96.0.1:8081", nil, true},
+		{"Isolated from the other namespaces", restricted, "10.96.0.2:8080", nil, true},
+		{"Other namespace through the gateway", restricted, "10.96.0.2:8080",
+			routeViaGateway, true},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		if test.route != nil {
+			if err := test.ns.configure(test.route); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		err := test.ns.Do(func() error {
+			conn, err := net.DialTimeout("tcp", test.address, 500*time.Millisecond)
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		})
+		if diff := cmp.Diff(test.expectError, err != nil); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+	// Only the NAT namespace is masqueraded
+	c, err := nftables.New()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	elements, err := c.GetSetElements(network.firewall.natSet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(1, len(elements)); diff != "" {
+		t.Fatalf("Unexpected result: %s", diff)
+	}
+	if diff := cmp.Diff("10.96.0.3", net.IP(elements[0].Key).String()); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	// Rules are removed on close
+	if err := restricted.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, set := range []*nftables.Set{network.firewall.inputMap,
+		network.firewall.forwardMap, network.firewall.natSet} {
+		elements, err := c.GetSetElements(set)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// Bridged namespace keeps its forward drop
+		expected := 0
+		if set == network.firewall.forwardMap {
+			expected = 1
+		}
+		if diff := cmp.Diff(expected, len(elements)); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+	chains, err := c.ListChainsOfTableFamily(network.firewall.table.Family)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, chain := range chains {
+		if chain.Name == getChainName("22222222-bbbb") {
+			t.Errorf("Expected chain %s to be deleted", chain.Name)
+		}
+	}
+}
+
+func TestEgressIPv6(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Requires root")
+	}
+	network, err := NewNetwork("trtest2", "10.95.0.0/24", t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() {
+		if link, err := netlink.LinkByName("trtest2"); err == nil {
+			netlink.LinkDel(link)
+		}
+		if c, err := nftables.New(); err == nil {
+			c.DelTable(network.firewall.table)
+			c.DelTable(network.firewall.bridgeTable)
+			c.Flush()
+		}
+	}()
+	// Host listens on every address, including link-local ones
+	listener, err := net.Listen("tcp", "[::]:8080")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer listener.Close()
+	rule, err := NewEgressRule("10.95.0.1/32", []uint16{8081})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restricted, err := network.Attach("33333333-cccc", ModeBridged, []EgressRule{rule})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer restricted.Close()
+	// Link-local addresses are usable once duplicate detection is over
+	h, err := netlink.NewHandle()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer h.Close()
+	hostIP := getLinkLocalIP(t, h, "trtest2")
+	if err := restricted.configure(func(h *netlink.Handle) error {
+		getLinkLocalIP(t, h, InterfaceName)
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	address := fmt.Sprintf("[%s%%%s]:8080", hostIP, InterfaceName)
+	if err := restricted.Do(func() error {
+		conn, err := net.DialTimeout("tcp", address, 500*time.Millisecond)
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}); err == nil {
+		t.Errorf("Expected host to be unreachable over IPv6")
+	}
+}
+
+// Waits for the link-local address of the interface, skipping the
+// test if it gets none
+func getLinkLocalIP(t *testing.T, h *netlink.Handle, name string) net.IP {
+	link, err := h.LinkByName(name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		addrs, err := h.AddrList(link, netlink.FAMILY_V6)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, addr := range addrs {
+			if addr.IP.IsLinkLocalUnicast() && addr.Flags&unix.IFA_F_TENTATIVE == 0 {
+				return addr.IP
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Skipf("Requires IPv6 link-local address on %s", name)
+
+	return nil
+}
+
+func TestEgressRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		cidr         string
+		ports        []uint16
+		ceiling      string
+		ceilingPorts []uint16
+		expectWithin bool
+	}{
+		{"Same network", "10.0.0.0/8", nil, "10.0.0.0/8", nil, true},
+		{"Subnet with ports", "10.1.0.0/16", []uint16{443}, "10.0.0.0/8", nil, true},
+		{"Wider network", "10.0.0.0/7", nil, "10.0.0.0/8", nil, false},
+		{"Other network", "192.168.0.0/16", nil, "10.0.0.0/8", nil, false},
+		{"Allowed ports", "0.0.0.0/0", []uint16{80, 443}, "0.0.0.0/0", []uint16{443, 80}, true},
+		{"Disallowed port", "0.0.0.0/0", []uint16{22}, "0.0.0.0/0", []uint16{443}, false},
+		{"Every port", "10.0.0.1/32", nil, "10.0.0.0/8", []uint16{443}, false},
+	}
+	for _, test := range tests {
+		t.Logf("Executing test: %s", test.name)
+		rule, err := NewEgressRule(test.cidr, test.ports)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ceiling, err := NewEgressRule(test.ceiling, test.ceilingPorts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(test.expectWithin, rule.IsWithin(ceiling)); diff != "" {
+			t.Errorf("Unexpected result: %s", diff)
+		}
+	}
+	for _, cidr := range []string{"10.0.0.0", "fd00::/8"} {
+		if _, err := NewEgressRule(cidr, nil); err == nil {
+			t.Errorf("Expected error for %s", cidr)
+		}
+	}
+	ports := []uint16{}
+	for port := uint16(1); port <= MaxEgressPorts+1; port++ {
+		ports = append(ports, port)
+	}
+	if _, err := NewEgressRule("10.0.0.0/8", ports); err == nil {
+		t.Errorf("Expected error for %d ports", len(ports))
+	}
+	// Duplicates are dropped
+	rules := []EgressRule{}
+	for _, ports := range [][]uint16{{443, 80, 443}, {80, 443}, nil} {
+		rule, err := NewEgressRule("10.0.0.0/8", ports)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	compacted, err := CompactEgressRules(rules)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := []string{}
+	for _, rule := range compacted {
+		got = append(got, rule.String())
+	}
+	if diff := cmp.Diff([]string{"10.0.0.0/8:80,443", "10.0.0.0/8"}, got); diff != "" {
+		t.Errorf("Unexpected result: %s", diff)
+	}
+	for len(compacted) <= MaxEgressRules {
+		rule, err := NewEgressRule(fmt.Sprintf("10.%d.0.0/16", len(compacted)), nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		compacted = append(compacted, rule)
+	}
+	if _, err := CompactEgressRules(compacted); err == nil {
+		t.Errorf("Expected error for %d rules", len(compacted))
+	}
+}
@@ -0,0 +1,52 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+)
+
+// Allocates a pseudo-terminal pair with given window size.
+// Master end stays with this process and slave end is
+// handed to the command.
+func openPTY(rows, cols uint16) (master *os.File, slave *os.File, err error) {
+	masterFD, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open /dev/ptmx: %w", err)
+	}
+	master = os.NewFile(uintptr(masterFD), "/dev/ptmx")
+	defer func() {
+		if err != nil {
+			master.Close()
+		}
+	}()
+	// Unlock and find the slave end
+	if err := unix.IoctlSetPointerInt(masterFD, unix.TIOCSPTLCK, 0); err != nil {
+		return nil, nil, fmt.Errorf("failed to unlock pty: %w", err)
+	}
+	ptyNum, err := unix.IoctlGetInt(masterFD, unix.TIOCGPTN)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pty number: %w", err)
+	}
+	if err := setPTYSize(master, rows, cols); err != nil {
+		return nil, nil, err
+	}
+	slavePath := "/dev/pts/" + strconv.Itoa(ptyNum)
+	slaveFD, err := unix.Open(slavePath, unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", slavePath, err)
+	}
+
+	return master, os.NewFile(uintptr(slaveFD), slavePath), nil
+}
+
+func setPTYSize(master *os.File, rows, cols uint16) error {
+	if err := unix.IoctlSetWinsize(int(master.Fd()), unix.TIOCSWINSZ,
+		&unix.Winsize{Row: rows, Col: cols}); err != nil {
+		return fmt.Errorf("failed to set pty size: %w", err)
+	}
+
+	return nil
+}
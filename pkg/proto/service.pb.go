@@ -26,7 +26,7 @@ var file_proto_service_proto_rawDesc = string([]byte{
 	0x0a, 0x13, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x32, 0xdd, 0x02, 0x0a, 0x0a, 0x4a, 0x6f, 0x62, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x74, 0x6f, 0x32, 0xf8, 0x05, 0x0a, 0x0a, 0x4a, 0x6f, 0x62, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
 	0x65, 0x12, 0x3b, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x16, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65,
 	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69,
@@ -35,52 +35,102 @@ var file_proto_service_proto_rawDesc = string([]byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
 	0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x4c, 0x61, 0x75, 0x6e, 0x63,
-	0x68, 0x4a, 0x6f, 0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x75,
-	0x6e, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x41, 0x74, 0x74, 0x61, 0x63,
-	0x68, 0x4a, 0x6f, 0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x74, 0x74,
-	0x61, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x47, 0x0a, 0x0c, 0x54, 0x65, 0x72,
-	0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65,
-	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x74, 0x72, 0x6f, 0x70, 0x6c, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x4a, 0x6f,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
+	0x65, 0x74, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a,
+	0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x09, 0x4c,
+	0x61, 0x75, 0x6e, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x61, 0x75, 0x6e, 0x63, 0x68,
+	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x41,
+	0x74, 0x74, 0x61, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x48, 0x0a,
+	0x0b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x4a, 0x6f, 0x62, 0x12, 0x19, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x4a, 0x6f, 0x62,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x61, 0x63, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x3e, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x6c, 0x4a, 0x6f, 0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x4a, 0x6f, 0x62, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x50, 0x61, 0x75, 0x73, 0x65,
+	0x4a, 0x6f, 0x62, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x4a, 0x6f,
+	0x62, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x65, 0x4a, 0x6f, 0x62, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x74, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1e, 0x5a,
+	0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x72, 0x6f, 0x70,
+	0x6c, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var file_proto_service_proto_goTypes = []any{
-	(*ListJobsRequest)(nil),      // 0: proto.ListJobsRequest
-	(*GetJobStatusRequest)(nil),  // 1: proto.GetJobStatusRequest
-	(*LaunchJobRequest)(nil),     // 2: proto.LaunchJobRequest
-	(*AttachJobRequest)(nil),     // 3: proto.AttachJobRequest
-	(*TerminateJobRequest)(nil),  // 4: proto.TerminateJobRequest
-	(*ListJobsResponse)(nil),     // 5: proto.ListJobsResponse
-	(*GetJobStatusResponse)(nil), // 6: proto.GetJobStatusResponse
-	(*LaunchJobResponse)(nil),    // 7: proto.LaunchJobResponse
-	(*AttachJobResponse)(nil),    // 8: proto.AttachJobResponse
-	(*TerminateJobResponse)(nil), // 9: proto.TerminateJobResponse
+	(*ListJobsRequest)(nil),       // 0: proto.ListJobsRequest
+	(*GetJobStatusRequest)(nil),   // 1: proto.GetJobStatusRequest
+	(*GetJobStatsRequest)(nil),    // 2: proto.GetJobStatsRequest
+	(*WatchJobStatsRequest)(nil),  // 3: proto.WatchJobStatsRequest
+	(*LaunchJobRequest)(nil),      // 4: proto.LaunchJobRequest
+	(*AttachJobRequest)(nil),      // 5: proto.AttachJobRequest
+	(*InteractJobRequest)(nil),    // 6: proto.InteractJobRequest
+	(*SignalJobRequest)(nil),      // 7: proto.SignalJobRequest
+	(*PauseJobRequest)(nil),       // 8: proto.PauseJobRequest
+	(*ResumeJobRequest)(nil),      // 9: proto.ResumeJobRequest
+	(*TerminateJobRequest)(nil),   // 10: proto.TerminateJobRequest
+	(*ListJobsResponse)(nil),      // 11: proto.ListJobsResponse
+	(*GetJobStatusResponse)(nil),  // 12: proto.GetJobStatusResponse
+	(*GetJobStatsResponse)(nil),   // 13: proto.GetJobStatsResponse
+	(*WatchJobStatsResponse)(nil), // 14: proto.WatchJobStatsResponse
+	(*LaunchJobResponse)(nil),     // 15: proto.LaunchJobResponse
+	(*AttachJobResponse)(nil),     // 16: proto.AttachJobResponse
+	(*InteractJobResponse)(nil),   // 17: proto.InteractJobResponse
+	(*SignalJobResponse)(nil),     // 18: proto.SignalJobResponse
+	(*PauseJobResponse)(nil),      // 19: proto.PauseJobResponse
+	(*ResumeJobResponse)(nil),     // 20: proto.ResumeJobResponse
+	(*TerminateJobResponse)(nil),  // 21: proto.TerminateJobResponse
 }
 var file_proto_service_proto_depIdxs = []int32{
-	0, // 0: proto.JobService.ListJobs:input_type -> proto.ListJobsRequest
-	1, // 1: proto.JobService.GetJobStatus:input_type -> proto.GetJobStatusRequest
-	2, // 2: proto.JobService.LaunchJob:input_type -> proto.LaunchJobRequest
-	3, // 3: proto.JobService.AttachJob:input_type -> proto.AttachJobRequest
-	4, // 4: proto.JobService.TerminateJob:input_type -> proto.TerminateJobRequest
-	5, // 5: proto.JobService.ListJobs:output_type -> proto.ListJobsResponse
-	6, // 6: proto.JobService.GetJobStatus:output_type -> proto.GetJobStatusResponse
-	7, // 7: proto.JobService.LaunchJob:output_type -> proto.LaunchJobResponse
-	8, // 8: proto.JobService.AttachJob:output_type -> proto.AttachJobResponse
-	9, // 9: proto.JobService.TerminateJob:output_type -> proto.TerminateJobResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: proto.JobService.ListJobs:input_type -> proto.ListJobsRequest
+	1,  // 1: proto.JobService.GetJobStatus:input_type -> proto.GetJobStatusRequest
+	2,  // 2: proto.JobService.GetJobStats:input_type -> proto.GetJobStatsRequest
+	3,  // 3: proto.JobService.WatchJobStats:input_type -> proto.WatchJobStatsRequest
+	4,  // 4: proto.JobService.LaunchJob:input_type -> proto.LaunchJobRequest
+	5,  // 5: proto.JobService.AttachJob:input_type -> proto.AttachJobRequest
+	6,  // 6: proto.JobService.InteractJob:input_type -> proto.InteractJobRequest
+	7,  // 7: proto.JobService.SignalJob:input_type -> proto.SignalJobRequest
+	8,  // 8: proto.JobService.PauseJob:input_type -> proto.PauseJobRequest
+	9,  // 9: proto.JobService.ResumeJob:input_type -> proto.ResumeJobRequest
+	10, // 10: proto.JobService.TerminateJob:input_type -> proto.TerminateJobRequest
+	11, // 11: proto.JobService.ListJobs:output_type -> proto.ListJobsResponse
+	12, // 12: proto.JobService.GetJobStatus:output_type -> proto.GetJobStatusResponse
+	13, // 13: proto.JobService.GetJobStats:output_type -> proto.GetJobStatsResponse
+	14, // 14: proto.JobService.WatchJobStats:output_type -> proto.WatchJobStatsResponse
+	15, // 15: proto.JobService.LaunchJob:output_type -> proto.LaunchJobResponse
+	16, // 16: proto.JobService.AttachJob:output_type -> proto.AttachJobResponse
+	17, // 17: proto.JobService.InteractJob:output_type -> proto.InteractJobResponse
+	18, // 18: proto.JobService.SignalJob:output_type -> proto.SignalJobResponse
+	19, // 19: proto.JobService.PauseJob:output_type -> proto.PauseJobResponse
+	20, // 20: proto.JobService.ResumeJob:output_type -> proto.ResumeJobResponse
+	21, // 21: proto.JobService.TerminateJob:output_type -> proto.TerminateJobResponse
+	11, // [11:22] is the sub-list for method output_type
+	0,  // [0:11] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_proto_service_proto_init() }
@@ -19,11 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	JobService_ListJobs_FullMethodName     = "/proto.JobService/ListJobs"
-	JobService_GetJobStatus_FullMethodName = "/proto.JobService/GetJobStatus"
-	JobService_LaunchJob_FullMethodName    = "/proto.JobService/LaunchJob"
-	JobService_AttachJob_FullMethodName    = "/proto.JobService/AttachJob"
-	JobService_TerminateJob_FullMethodName = "/proto.JobService/TerminateJob"
+	JobService_ListJobs_FullMethodName      = "/proto.JobService/ListJobs"
+	JobService_GetJobStatus_FullMethodName  = "/proto.JobService/GetJobStatus"
+	JobService_GetJobStats_FullMethodName   = "/proto.JobService/GetJobStats"
+	JobService_WatchJobStats_FullMethodName = "/proto.JobService/WatchJobStats"
+	JobService_LaunchJob_FullMethodName     = "/proto.JobService/LaunchJob"
+	JobService_AttachJob_FullMethodName     = "/proto.JobService/AttachJob"
+	JobService_InteractJob_FullMethodName   = "/proto.JobService/InteractJob"
+	JobService_SignalJob_FullMethodName     = "/proto.JobService/SignalJob"
+	JobService_PauseJob_FullMethodName      = "/proto.JobService/PauseJob"
+	JobService_ResumeJob_FullMethodName     = "/proto.JobService/ResumeJob"
+	JobService_TerminateJob_FullMethodName  = "/proto.JobService/TerminateJob"
 )
 
 // JobServiceClient is the client API for JobService service.
@@ -36,10 +42,29 @@ type JobServiceClient interface {
 	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
 	// Get the status of job either runing or terminated
 	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*GetJobStatusResponse, error)
+	// Get resource usage of a job. Running jobs are sampled, and the
+	// totals captured at exit are returned for terminated jobs.
+	GetJobStats(ctx context.Context, in *GetJobStatsRequest, opts ...grpc.CallOption) (*GetJobStatsResponse, error)
+	// Samples resource usage of a job at an interval till it terminates.
+	// The last response carries the totals captured at exit.
+	WatchJobStats(ctx context.Context, in *WatchJobStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchJobStatsResponse], error)
 	// Request a new job launch on the server side.
 	LaunchJob(ctx context.Context, in *LaunchJobRequest, opts ...grpc.CallOption) (*LaunchJobResponse, error)
-	// Attaches to a running job and gets its stderr, stdout streams.
+	// Attaches to a job and gets its stderr, stdout streams. Persisted
+	// output is replayed first, followed by the live output if running.
 	AttachJob(ctx context.Context, in *AttachJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AttachJobResponse], error)
+	// Attaches to a job like AttachJob and forwards the stdin sent by
+	// the client to the job. The job must be launched with open stdin.
+	InteractJob(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InteractJobRequest, InteractJobResponse], error)
+	// Sends a signal to the process group of running job. The job runs
+	// as init of its PID namespace, thus it does not get the signals it
+	// has no handler for, except KILL. Stop and continue signals are
+	// rejected, PauseJob and ResumeJob are to be used instead.
+	SignalJob(ctx context.Context, in *SignalJobRequest, opts ...grpc.CallOption) (*SignalJobResponse, error)
+	// Freezes all the processes of running job till it is resumed.
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
+	// Resumes paused job.
+	ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error)
 	// Request termination of running job.
 	TerminateJob(ctx context.Context, in *TerminateJobRequest, opts ...grpc.CallOption) (*TerminateJobResponse, error)
 }
@@ -72,6 +97,35 @@ func (c *jobServiceClient) GetJobStatus(ctx context.Context, in *GetJobStatusReq
 	return out, nil
 }
 
+func (c *jobServiceClient) GetJobStats(ctx context.Context, in *GetJobStatsRequest, opts ...grpc.CallOption) (*GetJobStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobStatsResponse)
+	err := c.cc.Invoke(ctx, JobService_GetJobStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobServiceClient) WatchJobStats(ctx context.Context, in *WatchJobStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchJobStatsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JobService_ServiceDesc.Streams[0], JobService_WatchJobStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobStatsRequest, WatchJobStatsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobService_WatchJobStatsClient = grpc.ServerStreamingClient[WatchJobStatsResponse]
+
 func (c *jobServiceClient) LaunchJob(ctx context.Context, in *LaunchJobRequest, opts ...grpc.CallOption) (*LaunchJobResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LaunchJobResponse)
@@ -84,7 +138,7 @@ func (c *jobServiceClient) LaunchJob(ctx context.Context, in *LaunchJobRequest,
 
 func (c *jobServiceClient) AttachJob(ctx context.Context, in *AttachJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AttachJobResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &JobService_ServiceDesc.Streams[0], JobService_AttachJob_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &JobService_ServiceDesc.Streams[1], JobService_AttachJob_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +155,49 @@ func (c *jobServiceClient) AttachJob(ctx context.Context, in *AttachJobRequest,
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type JobService_AttachJobClient = grpc.ServerStreamingClient[AttachJobResponse]
 
+func (c *jobServiceClient) InteractJob(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[InteractJobRequest, InteractJobResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &JobService_ServiceDesc.Streams[2], JobService_InteractJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InteractJobRequest, InteractJobResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobService_InteractJobClient = grpc.BidiStreamingClient[InteractJobRequest, InteractJobResponse]
+
+func (c *jobServiceClient) SignalJob(ctx context.Context, in *SignalJobRequest, opts ...grpc.CallOption) (*SignalJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SignalJobResponse)
+	err := c.cc.Invoke(ctx, JobService_SignalJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobServiceClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseJobResponse)
+	err := c.cc.Invoke(ctx, JobService_PauseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobServiceClient) ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeJobResponse)
+	err := c.cc.Invoke(ctx, JobService_ResumeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *jobServiceClient) TerminateJob(ctx context.Context, in *TerminateJobRequest, opts ...grpc.CallOption) (*TerminateJobResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TerminateJobResponse)
@@ -121,10 +218,29 @@ type JobServiceServer interface {
 	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
 	// Get the status of job either runing or terminated
 	GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error)
+	// Get resource usage of a job. Running jobs are sampled, and the
+	// totals captured at exit are returned for terminated jobs.
+	GetJobStats(context.Context, *GetJobStatsRequest) (*GetJobStatsResponse, error)
+	// Samples resource usage of a job at an interval till it terminates.
+	// The last response carries the totals captured at exit.
+	WatchJobStats(*WatchJobStatsRequest, grpc.ServerStreamingServer[WatchJobStatsResponse]) error
 	// Request a new job launch on the server side.
 	LaunchJob(context.Context, *LaunchJobRequest) (*LaunchJobResponse, error)
-	// Attaches to a running job and gets its stderr, stdout streams.
+	// Attaches to a job and gets its stderr, stdout streams. Persisted
+	// output is replayed first, followed by the live output if running.
 	AttachJob(*AttachJobRequest, grpc.ServerStreamingServer[AttachJobResponse]) error
+	// Attaches to a job like AttachJob and forwards the stdin sent by
+	// the client to the job. The job must be launched with open stdin.
+	InteractJob(grpc.BidiStreamingServer[InteractJobRequest, InteractJobResponse]) error
+	// Sends a signal to the process group of running job. The job runs
+	// as init of its PID namespace, thus it does not get the signals it
+	// has no handler for, except KILL. Stop and continue signals are
+	// rejected, PauseJob and ResumeJob are to be used instead.
+	SignalJob(context.Context, *SignalJobRequest) (*SignalJobResponse, error)
+	// Freezes all the processes of running job till it is resumed.
+	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
+	// Resumes paused job.
+	ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error)
 	// Request termination of running job.
 	TerminateJob(context.Context, *TerminateJobRequest) (*TerminateJobResponse, error)
 	mustEmbedUnimplementedJobServiceServer()
@@ -143,12 +259,30 @@ func (UnimplementedJobServiceServer) ListJobs(context.Context, *ListJobsRequest)
 func (UnimplementedJobServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedJobServiceServer) GetJobStats(context.Context, *GetJobStatsRequest) (*GetJobStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStats not implemented")
+}
+func (UnimplementedJobServiceServer) WatchJobStats(*WatchJobStatsRequest, grpc.ServerStreamingServer[WatchJobStatsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJobStats not implemented")
+}
 func (UnimplementedJobServiceServer) LaunchJob(context.Context, *LaunchJobRequest) (*LaunchJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LaunchJob not implemented")
 }
 func (UnimplementedJobServiceServer) AttachJob(*AttachJobRequest, grpc.ServerStreamingServer[AttachJobResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method AttachJob not implemented")
 }
+func (UnimplementedJobServiceServer) InteractJob(grpc.BidiStreamingServer[InteractJobRequest, InteractJobResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method InteractJob not implemented")
+}
+func (UnimplementedJobServiceServer) SignalJob(context.Context, *SignalJobRequest) (*SignalJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignalJob not implemented")
+}
+func (UnimplementedJobServiceServer) PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedJobServiceServer) ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeJob not implemented")
+}
 func (UnimplementedJobServiceServer) TerminateJob(context.Context, *TerminateJobRequest) (*TerminateJobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TerminateJob not implemented")
 }
@@ -209,6 +343,35 @@ func _JobService_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _JobService_GetJobStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobServiceServer).GetJobStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobService_GetJobStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobServiceServer).GetJobStats(ctx, req.(*GetJobStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobService_WatchJobStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobServiceServer).WatchJobStats(m, &grpc.GenericServerStream[WatchJobStatsRequest, WatchJobStatsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobService_WatchJobStatsServer = grpc.ServerStreamingServer[WatchJobStatsResponse]
+
 func _JobService_LaunchJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LaunchJobRequest)
 	if err := dec(in); err != nil {
@@ -238,6 +401,67 @@ func _JobService_AttachJob_Handler(srv interface{}, stream grpc.ServerStream) er
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type JobService_AttachJobServer = grpc.ServerStreamingServer[AttachJobResponse]
 
+func _JobService_InteractJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JobServiceServer).InteractJob(&grpc.GenericServerStream[InteractJobRequest, InteractJobResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type JobService_InteractJobServer = grpc.BidiStreamingServer[InteractJobRequest, InteractJobResponse]
+
+func _JobService_SignalJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignalJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobServiceServer).SignalJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobService_SignalJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobServiceServer).SignalJob(ctx, req.(*SignalJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobService_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobServiceServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobService_PauseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobServiceServer).PauseJob(ctx, req.(*PauseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobService_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobServiceServer).ResumeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobService_ResumeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobServiceServer).ResumeJob(ctx, req.(*ResumeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _JobService_TerminateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TerminateJobRequest)
 	if err := dec(in); err != nil {
@@ -271,21 +495,48 @@ var JobService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJobStatus",
 			Handler:    _JobService_GetJobStatus_Handler,
 		},
+		{
+			MethodName: "GetJobStats",
+			Handler:    _JobService_GetJobStats_Handler,
+		},
 		{
 			MethodName: "LaunchJob",
 			Handler:    _JobService_LaunchJob_Handler,
 		},
+		{
+			MethodName: "SignalJob",
+			Handler:    _JobService_SignalJob_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _JobService_PauseJob_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _JobService_ResumeJob_Handler,
+		},
 		{
 			MethodName: "TerminateJob",
 			Handler:    _JobService_TerminateJob_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJobStats",
+			Handler:       _JobService_WatchJobStats_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "AttachJob",
 			Handler:       _JobService_AttachJob_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "InteractJob",
+			Handler:       _JobService_InteractJob_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/service.proto",
 }